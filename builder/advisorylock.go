@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrLockTimeout is returned when an advisory lock is held elsewhere for
+// longer than the requested timeout.
+var ErrLockTimeout = errors.New("builder: timed out waiting for advisory lock")
+
+// WithAdvisoryLock runs fn while holding the named MySQL advisory lock
+// (GET_LOCK), so only one process at a time runs it, e.g. a cron job or a
+// migration deployed to several hosts. It waits up to timeout (rounded up to
+// whole seconds; negative waits forever) and returns ErrLockTimeout if the
+// lock stays taken.
+//
+// The lock belongs to a dedicated connection held for the duration of fn and
+// is released afterwards, also when fn panics.
+func (d *DB) WithAdvisoryLock(name string, timeout time.Duration, fn func() error) error {
+	return d.WithAdvisoryLockContext(context.Background(), name, timeout, fn)
+}
+
+// WithAdvisoryLockContext is WithAdvisoryLock with a context, used for
+// acquiring and releasing the lock.
+func (d *DB) WithAdvisoryLockContext(ctx context.Context, name string, timeout time.Duration, fn func() error) (err error) {
+	if name == "" || len(name) > 64 {
+		return fmt.Errorf("invalid advisory lock name: %q", name)
+	}
+
+	conn, err := d.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	seconds := -1
+	if timeout >= 0 {
+		seconds = int(math.Ceil(timeout.Seconds()))
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, seconds).Scan(&acquired); err != nil {
+		return fmt.Errorf("get advisory lock %s: %w", name, err)
+	}
+	if !acquired.Valid {
+		return fmt.Errorf("get advisory lock %s: failed", name)
+	}
+	if acquired.Int64 != 1 {
+		return ErrLockTimeout
+	}
+
+	defer func() {
+		if _, releaseErr := conn.ExecContext(context.Background(), "DO RELEASE_LOCK(?)", name); releaseErr != nil {
+			// Closing the connection releases the lock too.
+			discard(conn)
+			if err == nil {
+				err = fmt.Errorf("release advisory lock %s: %w", name, releaseErr)
+			}
+		}
+	}()
+
+	return fn()
+}
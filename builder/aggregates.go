@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// CountBy counts the matching rows per value of column, with
+// SELECT column, COUNT(*) ... GROUP BY column. Values are keyed in their
+// text form; NULL is keyed as "".
+func (qb *QueryBuilder) CountBy(column string) (map[string]int64, error) {
+	if !utils.IsValidIdentifier(column) {
+		return nil, fmt.Errorf("invalid column name: %q", column)
+	}
+
+	qb.columns = []string{column, "COUNT(*)"}
+	qb.groupBy = column
+	query, params := qb.Build()
+
+	counts := make(map[string]int64)
+	err := qb.withSessionVars(func() error {
+		rows, err := qb.query(query, params)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var value sql.NullString
+			var count int64
+			if err := rows.Scan(&value, &count); err != nil {
+				return err
+			}
+			counts[value.String] += count
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ColumnStats summarises a numeric column. Count is the number of non-NULL
+// values; the other fields are zero when there are none.
+type ColumnStats struct {
+	Count  int64
+	Min    float64
+	Max    float64
+	Sum    float64
+	Avg    float64
+	StdDev float64 // population standard deviation
+}
+
+// Stats computes the count, min, max, sum, average and standard deviation of
+// column in one query, instead of a round trip per aggregate.
+func (qb *QueryBuilder) Stats(column string) (ColumnStats, error) {
+	if !utils.IsValidIdentifier(column) {
+		return ColumnStats{}, fmt.Errorf("invalid column name: %q", column)
+	}
+
+	qb.columns = []string{
+		"COUNT(" + column + ")",
+		"MIN(" + column + ")",
+		"MAX(" + column + ")",
+		"SUM(" + column + ")",
+		"AVG(" + column + ")",
+		"STDDEV_POP(" + column + ")",
+	}
+	query, params := qb.Build()
+
+	var stats ColumnStats
+	var min, max, sum, avg, stddev sql.NullFloat64
+	if err := qb.scanOne(query, params, &stats.Count, &min, &max, &sum, &avg, &stddev); err != nil {
+		return ColumnStats{}, err
+	}
+	stats.Min, stats.Max, stats.Sum = min.Float64, max.Float64, sum.Float64
+	stats.Avg, stats.StdDev = avg.Float64, stddev.Float64
+
+	return stats, nil
+}
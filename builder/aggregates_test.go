@@ -0,0 +1,14 @@
+package builder
+
+import "testing"
+
+func TestAggregatesRejectInvalidColumns(t *testing.T) {
+	for _, column := range []string{"amount) FROM secrets --", "a b", ""} {
+		if _, err := testDB(t).Table("orders").Stats(column); err == nil {
+			t.Errorf("Stats(%q): expected an error", column)
+		}
+		if _, err := testDB(t).Table("orders").CountBy(column); err == nil {
+			t.Errorf("CountBy(%q): expected an error", column)
+		}
+	}
+}
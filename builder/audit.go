@@ -0,0 +1,197 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const defaultAuditTable = "audit_logs"
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor recorded in audit entries.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+
+	return actor
+}
+
+// EnableAudit records every Insert, Update and Delete on the given tables in
+// the audit table (audit_logs unless changed with SetAuditTable), inside the
+// same transaction as the write. The audit table needs the columns
+// table_name, action, old_values, new_values, actor and created_at.
+func (d *DB) EnableAudit(tables ...string) *DB {
+	if d.auditTables == nil {
+		d.auditTables = make(map[string]bool)
+	}
+	for _, table := range tables {
+		d.auditTables[table] = true
+	}
+
+	return d
+}
+
+// SetAuditTable changes the table audit entries are written to.
+func (d *DB) SetAuditTable(table string) *DB {
+	d.auditTable = table
+
+	return d
+}
+
+func (qb *QueryBuilder) audited() bool {
+	return qb.db.auditTables[qb.table]
+}
+
+// execWrite runs an INSERT, UPDATE or DELETE statement; columns names the
+// column each parameter is bound against. On audited tables the
+// rows touched are recorded alongside the statement in one transaction:
+// values holds the inserted rows, or the SET values of an update.
+func (qb *QueryBuilder) execWrite(action, query string, params []interface{}, columns []string, values []map[string]interface{}) (sql.Result, error) {
+	if !qb.audited() {
+		return qb.exec(query, params, columns)
+	}
+
+	var result sql.Result
+	err := qb.inTransaction(func() error {
+		var before []map[string]interface{}
+		if action != "insert" {
+			var err error
+			if before, err = qb.auditedRows(); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if result, err = qb.exec(query, params, columns); err != nil {
+			return err
+		}
+
+		switch action {
+		case "insert":
+			if n, err := result.RowsAffected(); err == nil && n == 0 {
+				return nil // a conditional insert that inserted nothing
+			}
+			if len(values) == 1 {
+				values = []map[string]interface{}{qb.withInsertID(values[0], result)}
+			}
+			for _, row := range values {
+				if err := qb.writeAudit(action, nil, row); err != nil {
+					return err
+				}
+			}
+		case "update":
+			for _, old := range before {
+				updated := make(map[string]interface{}, len(old))
+				for column, value := range old {
+					updated[column] = value
+				}
+				for column, value := range values[0] {
+					updated[column] = value
+				}
+				if err := qb.writeAudit(action, old, updated); err != nil {
+					return err
+				}
+			}
+		case "delete":
+			for _, old := range before {
+				if err := qb.writeAudit(action, old, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// withInsertID returns row with the AUTO_INCREMENT key the insert generated,
+// so the audit entry identifies the row. Rows that carry their key, and
+// multi-row inserts, whose generated keys need not be consecutive, are
+// recorded as given.
+func (qb *QueryBuilder) withInsertID(row map[string]interface{}, result sql.Result) map[string]interface{} {
+	key, err := qb.primaryKeyColumn()
+	if err != nil {
+		return row
+	}
+	if value, ok := row[key]; ok && value != nil {
+		return row
+	}
+	id, err := result.LastInsertId()
+	if err != nil || id == 0 {
+		return row
+	}
+
+	withID := make(map[string]interface{}, len(row)+1)
+	for column, value := range row {
+		withID[column] = value
+	}
+	withID[key] = id
+
+	return withID
+}
+
+// auditedRows loads and locks the rows the builder's WHERE clause matches.
+// Locking reads the latest committed rows, the ones the write will change,
+// rather than the transaction's snapshot.
+func (qb *QueryBuilder) auditedRows() ([]map[string]interface{}, error) {
+	query := fmt.Sprintf("SELECT * FROM %s%s", qb.from(), qb.whereClause())
+	if qb.db.Dialect().Name() != "sqlite" {
+		query += " FOR UPDATE"
+	}
+	rows, err := qb.query(query, qb.parameters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result, _, err := scanRows(rows, 0)
+
+	return result, err
+}
+
+func (qb *QueryBuilder) writeAudit(action string, oldValues, newValues map[string]interface{}) error {
+	oldJSON, err := auditJSON(oldValues)
+	if err != nil {
+		return err
+	}
+	newJSON, err := auditJSON(newValues)
+	if err != nil {
+		return err
+	}
+
+	table := qb.db.auditTable
+	if table == "" {
+		table = defaultAuditTable
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (table_name,action,old_values,new_values,actor,created_at) VALUES (?,?,?,?,?,?)", table)
+	_, err = qb.exec(query, []interface{}{qb.table, action, oldJSON, newJSON, ActorFromContext(qb.context()), time.Now()}, nil)
+
+	return err
+}
+
+func auditJSON(values map[string]interface{}) (interface{}, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding audit values: %w", err)
+	}
+
+	return string(encoded), nil
+}
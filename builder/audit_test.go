@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+type insertResult int64
+
+func (r insertResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r insertResult) RowsAffected() (int64, error) { return 1, nil }
+
+func TestAuditRecordsInsertID(t *testing.T) {
+	qb := testDB(t).Table("users")
+
+	row := map[string]interface{}{"name": "jane"}
+	got := qb.withInsertID(row, insertResult(42))
+	if want := map[string]interface{}{"name": "jane", "id": int64(42)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, ok := row["id"]; ok {
+		t.Error("withInsertID modified the inserted data")
+	}
+
+	keyed := map[string]interface{}{"id": "0190a", "name": "jane"}
+	if got := qb.withInsertID(keyed, insertResult(42)); got["id"] != "0190a" {
+		t.Errorf("existing key replaced: %v", got)
+	}
+}
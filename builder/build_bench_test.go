@@ -0,0 +1,100 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func benchmarkQuery(tb testing.TB) *QueryBuilder {
+	return testDB(tb).Table("users").
+		Select("id", "name", "email").
+		Join("INNER", "orders", "orders.user_id = users.id").
+		Where("status", "=", "active").
+		Where("age", ">", 18).
+		WhereIn("role", []interface{}{"admin", "editor"}).
+		OrderBy("id DESC").
+		Limit(20).
+		Offset(40)
+}
+
+// buildWithSprintf assembles the query the way Build did before it wrote
+// into pooled buffers, as the baseline of BenchmarkBuild.
+func buildWithSprintf(qb *QueryBuilder) (string, []interface{}) {
+	var query strings.Builder
+	if len(qb.columns) > 0 {
+		query.WriteString("SELECT " + strings.Join(qb.columns, ", "))
+	} else {
+		query.WriteString("SELECT *")
+	}
+	query.WriteString(" FROM " + qb.table)
+	if len(qb.joins) > 0 {
+		query.WriteString(" " + strings.Join(qb.joins, " "))
+	}
+	if len(qb.where) > 0 {
+		query.WriteString(" WHERE ")
+		for i, condition := range qb.where {
+			if i > 0 {
+				if strings.HasPrefix(condition, "OR ") {
+					query.WriteString(" ")
+				} else {
+					query.WriteString(" AND ")
+				}
+			}
+			query.WriteString(condition)
+		}
+	}
+	if qb.groupBy != "" {
+		query.WriteString(" GROUP BY " + qb.groupBy)
+	}
+	if len(qb.having) > 0 {
+		query.WriteString(" HAVING " + strings.Join(qb.having, " AND "))
+	}
+	if qb.orderBy != "" {
+		query.WriteString(" ORDER BY " + qb.orderBy)
+	}
+	if qb.limit >= 0 {
+		query.WriteString(fmt.Sprintf(" LIMIT %d", qb.limit))
+	}
+	if qb.offset >= 0 {
+		query.WriteString(fmt.Sprintf(" OFFSET %d", qb.offset))
+	}
+
+	return query.String(), qb.boundParams()
+}
+
+func TestBuildWithSprintfMatchesBuild(t *testing.T) {
+	qb := benchmarkQuery(t)
+	want, _ := qb.Build()
+	if got, _ := buildWithSprintf(qb); got != want {
+		t.Errorf("baseline builds %s\nBuild builds  %s", got, want)
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	qb := benchmarkQuery(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qb.Build()
+	}
+}
+
+func BenchmarkBuildWithSprintf(b *testing.B) {
+	qb := benchmarkQuery(b)
+	qb.Build() // apply implicit conditions once, as Build does
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildWithSprintf(qb)
+	}
+}
+
+func BenchmarkBuildSelectQuery(b *testing.B) {
+	qb := benchmarkQuery(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qb.BuildSelectQuery()
+	}
+}
@@ -1,17 +1,40 @@
 package builder
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/ruhulfbr/go-mysql-qb/db"
 	"github.com/ruhulfbr/go-mysql-qb/utils"
 	"strings"
+	"sync"
+	"time"
 )
 
 var DBConnection *sql.DB
 
+// ErrNoRows is returned by First when the query matches no rows.
+// It wraps sql.ErrNoRows, so errors.Is works against either value.
+var ErrNoRows = fmt.Errorf("builder: no rows in result set: %w", sql.ErrNoRows)
+
+// ErrNoConditions is returned by Update on a builder without conditions, which
+// would rewrite every row; use UpdateAll for that.
+var ErrNoConditions = errors.New("builder: update without conditions")
+
+// QueryBuilder accumulates the clauses of one statement. Its methods mutate
+// it in place, so a builder must not be shared between goroutines or reused
+// across requests; share a Frozen snapshot instead.
 type QueryBuilder struct {
+	db         *DB
+	runner     Runner
+	ctx        context.Context
+	err        error // first builder misuse, reported when the query runs
+	comments   []string
+	timezone   string
+	location   *time.Location
 	table      string
+	partitions []string
 	columns    []string
 	joins      []string
 	where      []string
@@ -20,19 +43,71 @@ type QueryBuilder struct {
 	having     []string
 	limit      int
 	offset     int
+	lock       string
+	lockWait   string
 	parameters []interface{}
+	rowLimit   *rowLimit
+	truncated  bool
+
+	captured     *Result
+	captureStart time.Time
+
+	debug   bool
+	origins []clauseOrigin
+
+	sessionVars   []sessionVar
+	sessionActive bool
+
+	contextScopes []ScopeFunc
+
+	txOptions []TxOption
+
+	tenantBypass  bool
+	filtersBypass bool
+	orderBypass   bool
+	implicitDone  bool
+
+	// conditioned records whether the caller added conditions before the
+	// implicit ones, implicitWhere how many conditions there were after them,
+	// so Update can refuse to rewrite every row.
+	conditioned   bool
+	implicitWhere int
+
+	// paramColumns holds the column each parameter is bound against ("" when
+	// unknown), used to mask sensitive values in logs.
+	paramColumns []string
+
+	// havingParams are bound after the WHERE parameters, matching clause order.
+	havingParams []interface{}
+
+	// selectParams holds the parameters of select expressions, by expression,
+	// bound before the WHERE parameters. Keying them by expression drops them
+	// when an aggregate replaces the columns.
+	selectParams map[string][]interface{}
 }
 
 func Table(ConnInstance *sql.DB, table string) *QueryBuilder {
 	DBConnection = ConnInstance
 
-	db.IsConnected(DBConnection)
+	return NewDB(ConnInstance).Table(table)
+}
+
+// bind appends parameters compared against column.
+func (qb *QueryBuilder) bind(column string, values ...interface{}) {
+	qb.traceClause("parameter for %s (%d)", column, len(values))
+	for _, value := range values {
+		qb.parameters = append(qb.parameters, value)
+		qb.paramColumns = append(qb.paramColumns, column)
+	}
+}
 
-	return &QueryBuilder{
-		table:  table,
-		limit:  -1,
-		offset: -1,
+// placeholders returns n comma-separated "?" placeholders.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
 	}
+
+	return strings.Repeat("?, ", n-1) + "?"
 }
 
 func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
@@ -41,13 +116,25 @@ func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
 	return qb
 }
 
+// Where adds "field operator ?". A nil value (including a nil pointer or a
+// NULL sql.Null* value) with = or != becomes IS NULL or IS NOT NULL, since
+// "field = NULL" never matches.
 func (qb *QueryBuilder) Where(field string, operator string, value interface{}) *QueryBuilder {
 	utils.IsValidOperator(operator)
 
-	condition := fmt.Sprintf("%s %s ?", field, operator)
+	if isNull(value) && (operator == "=" || operator == "!=") {
+		if operator == "=" {
+			qb.where = append(qb.where, field+" IS NULL")
+		} else {
+			qb.where = append(qb.where, field+" IS NOT NULL")
+		}
+		return qb
+	}
+
+	condition := field + " " + operator + " ?"
 
 	qb.where = append(qb.where, condition)
-	qb.parameters = append(qb.parameters, value)
+	qb.bind(field, value)
 
 	return qb
 }
@@ -55,73 +142,66 @@ func (qb *QueryBuilder) Where(field string, operator string, value interface{})
 func (qb *QueryBuilder) OrWhere(field string, operator string, value interface{}) *QueryBuilder {
 	utils.IsValidOperator(operator)
 
-	condition := fmt.Sprintf("OR %s %s ?", field, operator)
+	condition := "OR " + field + " " + operator + " ?"
 
 	qb.where = append(qb.where, condition)
-	qb.parameters = append(qb.parameters, value)
+	qb.bind(field, value)
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereIn(column string, values []interface{}) *QueryBuilder {
-	placeholders := make([]string, len(values))
-	for i := range values {
-		placeholders[i] = "?"
-		qb.parameters = append(qb.parameters, values[i])
-	}
-	qb.where = append(qb.where, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+	qb.bind(column, values...)
+	qb.where = append(qb.where, column+" IN ("+placeholders(len(values))+")")
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereNotIn(column string, values []interface{}) *QueryBuilder {
-	placeholders := make([]string, len(values))
-	for i := range values {
-		placeholders[i] = "?"
-		qb.parameters = append(qb.parameters, values[i])
-	}
-	qb.where = append(qb.where, fmt.Sprintf("%s NOT IN (%s)", column, strings.Join(placeholders, ", ")))
+	qb.bind(column, values...)
+	qb.where = append(qb.where, column+" NOT IN ("+placeholders(len(values))+")")
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereNull(column string) *QueryBuilder {
-	qb.where = append(qb.where, fmt.Sprintf("%s IS NULL", column))
+	qb.where = append(qb.where, column+" IS NULL")
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereLike(column string, value string) *QueryBuilder {
-	qb.where = append(qb.where, fmt.Sprintf("%s LIKE ?", column))
-	qb.parameters = append(qb.parameters, value)
+	qb.where = append(qb.where, column+" LIKE ?")
+	qb.bind(column, value)
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereNotLike(column string, value string) *QueryBuilder {
-	qb.where = append(qb.where, fmt.Sprintf("%s NOT LIKE ?", column))
-	qb.parameters = append(qb.parameters, value)
+	qb.where = append(qb.where, column+" NOT LIKE ?")
+	qb.bind(column, value)
 
 	return qb
 }
 
 func (qb *QueryBuilder) WhereBetween(column string, start, end interface{}) *QueryBuilder {
-	qb.where = append(qb.where, fmt.Sprintf("%s BETWEEN ? AND ?", column))
-	qb.parameters = append(qb.parameters, start, end)
+	qb.where = append(qb.where, column+" BETWEEN ? AND ?")
+	qb.bind(column, start, end)
 
 	return qb
 }
 
 func (qb *QueryBuilder) DateBetween(column string, start string, end string) *QueryBuilder {
-	qb.where = append(qb.where, fmt.Sprintf("%s BETWEEN ? AND ?", column))
-	qb.parameters = append(qb.parameters, start, end)
+	qb.where = append(qb.where, column+" BETWEEN ? AND ?")
+	qb.bind(column, start, end)
 
 	return qb
 }
 
 func (qb *QueryBuilder) Join(joinType, table, condition string) *QueryBuilder {
-	join := fmt.Sprintf("%s JOIN %s ON %s", joinType, table, condition)
+	join := joinType + " JOIN " + table + " ON " + condition
 	qb.joins = append(qb.joins, join)
+	qb.traceClause("%s", join)
 
 	return qb
 }
@@ -146,7 +226,8 @@ func (qb *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
 
 func (qb *QueryBuilder) Having(condition string, params ...interface{}) *QueryBuilder {
 	qb.having = append(qb.having, condition)
-	qb.parameters = append(qb.parameters, params...)
+	qb.havingParams = append(qb.havingParams, params...)
+	qb.traceClause("HAVING %s (%d parameters)", condition, len(params))
 
 	return qb
 }
@@ -171,105 +252,322 @@ func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
 
 // Build query based on mysql grammar
 func (qb *QueryBuilder) Build() (string, []interface{}) {
-	var query strings.Builder
+	qb.applyImplicit()
+	buf := getBuffer(qb.estimateSize())
+	defer putBuffer(buf)
+
+	qb.writeSelect(buf)
+
+	// ORDER BY clause
+	if qb.orderBy != "" {
+		buf.WriteString(" ORDER BY ")
+		buf.WriteString(qb.orderBy)
+	}
+
+	// LIMIT and OFFSET clauses
+	buf.WriteString(qb.db.Dialect().LimitOffset(qb.limit, qb.offset))
+
+	// Locking clause
+	if lock := qb.lockClause(); lock != "" {
+		buf.WriteString(" ")
+		buf.WriteString(lock)
+	}
+
+	return buf.String(), qb.boundParams()
+}
+
+// BuildSelectQuery is a helper for building the core SELECT query.
+func (qb *QueryBuilder) BuildSelectQuery() string {
+	qb.applyImplicit()
+	buf := getBuffer(qb.estimateSize())
+	defer putBuffer(buf)
+
+	qb.writeSelect(buf)
+
+	return buf.String()
+}
+
+// applyImplicit adds the clauses the DB applies to every query of the table
+// (global filters, context scopes, tenant scoping, default order). It runs
+// once, before the query is first rendered.
+func (qb *QueryBuilder) applyImplicit() {
+	if qb.implicitDone {
+		return
+	}
+	qb.implicitDone = true
+	qb.conditioned = len(qb.where) > 0
+
+	qb.applyGlobalFilters()
+	qb.applyContextScopes()
+	qb.applyTenant()
+	qb.applyDefaultOrder()
+	qb.implicitWhere = len(qb.where)
+}
+
+// writeSelect writes the SELECT, FROM, JOIN, WHERE, GROUP BY and HAVING clauses.
+func (qb *QueryBuilder) writeSelect(buf *bytes.Buffer) {
+	// checked on every render, as aggregates replace the columns
+	qb.applyColumnRestrictions()
 
 	// SELECT clause
+	buf.WriteString("SELECT ")
 	if len(qb.columns) > 0 {
-		query.WriteString("SELECT " + strings.Join(qb.columns, ", "))
+		writeJoined(buf, qb.columns, ", ")
 	} else {
-		query.WriteString("SELECT *")
+		buf.WriteString("*")
 	}
 
 	// FROM clause
-	query.WriteString(" FROM " + qb.table)
+	buf.WriteString(" FROM ")
+	buf.WriteString(qb.from())
 
 	// JOIN clauses
 	if len(qb.joins) > 0 {
-		query.WriteString(" " + strings.Join(qb.joins, " "))
+		buf.WriteString(" ")
+		writeJoined(buf, qb.joins, " ")
 	}
 
 	// WHERE clause
-	if len(qb.where) > 0 {
-		query.WriteString(" WHERE " + strings.Join(qb.where, " AND "))
+	qb.writeWhere(buf)
+
+	// GROUP BY and HAVING clauses
+	qb.writeGroupBy(buf)
+}
+
+// estimateSize returns the approximate length of the built query, so the
+// buffer grows once.
+func (qb *QueryBuilder) estimateSize() int {
+	size := 64 + len(qb.table) + len(qb.orderBy) + len(qb.groupBy)
+	for _, parts := range [][]string{qb.columns, qb.joins, qb.where, qb.having} {
+		for _, part := range parts {
+			size += len(part) + 5
+		}
 	}
 
-	// ORDER BY clause
-	if qb.orderBy != "" {
-		query.WriteString(" ORDER BY " + qb.orderBy)
+	return size
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer(size int) *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.Grow(size)
+
+	return buf
+}
+
+// putBuffer returns buf to the pool unless it grew unusually large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > 64<<10 {
+		return
 	}
+	bufferPool.Put(buf)
+}
 
-	// LIMIT clause
-	if qb.limit >= 0 {
-		query.WriteString(fmt.Sprintf(" LIMIT %d", qb.limit))
+func writeJoined(buf *bytes.Buffer, parts []string, sep string) {
+	for i, part := range parts {
+		if i > 0 {
+			buf.WriteString(sep)
+		}
+		buf.WriteString(part)
 	}
+}
 
-	// OFFSET clause
-	if qb.offset >= 0 {
-		query.WriteString(fmt.Sprintf(" OFFSET %d", qb.offset))
+// boundParams returns the parameters in clause order: WHERE, then HAVING.
+func (qb *QueryBuilder) boundParams() []interface{} {
+	selected := qb.selectedParams()
+	if len(qb.havingParams) == 0 && len(selected) == 0 {
+		return qb.parameters
 	}
 
-	return query.String(), qb.parameters
+	params := make([]interface{}, 0, len(selected)+len(qb.parameters)+len(qb.havingParams))
+	params = append(params, selected...)
+	params = append(params, qb.parameters...)
+
+	return append(params, qb.havingParams...)
 }
 
-// BuildSelectQuery is a helper for building the core SELECT query.
-func (qb *QueryBuilder) BuildSelectQuery() string {
-	var query strings.Builder
+// boundColumns returns the column of each parameter from boundParams.
+func (qb *QueryBuilder) boundColumns() []string {
+	selected := qb.selectedParams()
+	if len(qb.havingParams) == 0 && len(selected) == 0 {
+		return qb.paramColumns
+	}
 
-	// SELECT clause
-	if len(qb.columns) > 0 {
-		query.WriteString("SELECT " + strings.Join(qb.columns, ", "))
-	} else {
-		query.WriteString("SELECT *")
+	columns := make([]string, len(selected), len(selected)+len(qb.paramColumns)+len(qb.havingParams))
+	columns = append(columns, qb.paramColumns...)
+
+	return append(columns, make([]string, len(qb.havingParams))...)
+}
+
+// selectedParams returns the parameters of the selected expressions, in
+// select-list order.
+func (qb *QueryBuilder) selectedParams() []interface{} {
+	if len(qb.selectParams) == 0 {
+		return nil
 	}
 
-	// FROM clause
-	query.WriteString(" FROM " + qb.table)
+	var params []interface{}
+	for _, column := range qb.columns {
+		params = append(params, qb.selectParams[column]...)
+	}
 
-	// JOIN clauses
-	if len(qb.joins) > 0 {
-		query.WriteString(" " + strings.Join(qb.joins, " "))
+	return params
+}
+
+// selectWithParams adds a select expression whose ? placeholders bind params.
+func (qb *QueryBuilder) selectWithParams(expr string, params ...interface{}) *QueryBuilder {
+	if qb.selectParams == nil {
+		qb.selectParams = make(map[string][]interface{})
 	}
+	qb.selectParams[expr] = params
+	qb.columns = append(qb.columns, expr)
 
-	// WHERE clause
-	if len(qb.where) > 0 {
-		query.WriteString(" WHERE " + strings.Join(qb.where, " AND "))
+	return qb
+}
+
+// writeGroupBy writes the GROUP BY and HAVING clauses.
+func (qb *QueryBuilder) writeGroupBy(buf *bytes.Buffer) {
+	if qb.groupBy != "" {
+		buf.WriteString(" GROUP BY ")
+		buf.WriteString(qb.groupBy)
+	}
+	if len(qb.having) > 0 {
+		buf.WriteString(" HAVING ")
+		writeJoined(buf, qb.having, " AND ")
+	}
+}
+
+// writeWhere writes the WHERE clause, if there are conditions. Conditions
+// added by OrWhere carry their own "OR" and are not joined with AND.
+func (qb *QueryBuilder) writeWhere(buf *bytes.Buffer) {
+	if len(qb.where) == 0 {
+		return
+	}
+
+	buf.WriteString(" WHERE ")
+	for i, condition := range qb.where {
+		if i > 0 {
+			if strings.HasPrefix(condition, "OR ") {
+				buf.WriteString(" ")
+			} else {
+				buf.WriteString(" AND ")
+			}
+		}
+		buf.WriteString(condition)
 	}
+}
+
+// groupWhere collapses the current conditions into one parenthesized
+// condition, so conditions appended afterwards apply to all of them.
+func (qb *QueryBuilder) groupWhere() {
+	if len(qb.where) < 2 {
+		return
+	}
+
+	buf := getBuffer(qb.estimateSize())
+	defer putBuffer(buf)
+	qb.writeWhere(buf)
+
+	qb.where = []string{"(" + strings.TrimPrefix(buf.String(), " WHERE ") + ")"}
+}
 
-	return query.String()
+// whereClause renders the WHERE clause, or "" when there are no conditions.
+func (qb *QueryBuilder) whereClause() string {
+	qb.applyImplicit()
+	if len(qb.where) == 0 {
+		return ""
+	}
+
+	buf := getBuffer(qb.estimateSize())
+	defer putBuffer(buf)
+	qb.writeWhere(buf)
+
+	return buf.String()
 }
 
 // Get fetches multiple rows and returns them as an array of maps (like Laravel).
+// A query matching no rows yields an empty, non-nil slice.
 func (qb *QueryBuilder) Get() ([]map[string]interface{}, error) {
+	limit := qb.limit
+	qb.limit = qb.guardLimit()
 	query, params := qb.Build()
-	rows, err := DBConnection.Query(query, params...)
-	if err != nil {
-		return nil, err
+	qb.limit = limit
+
+	return qb.fetch(query, params)
+}
+
+// scanBuffers holds the per-row scan destinations, reused across rows and,
+// through scanPool, across calls.
+type scanBuffers struct {
+	values    []interface{}
+	valuePtrs []interface{}
+}
+
+var scanPool = sync.Pool{
+	New: func() interface{} {
+		return new(scanBuffers)
+	},
+}
+
+func getScanBuffers(n int) *scanBuffers {
+	buffers := scanPool.Get().(*scanBuffers)
+	if cap(buffers.values) < n {
+		buffers.values = make([]interface{}, n)
+		buffers.valuePtrs = make([]interface{}, n)
+	}
+	buffers.values = buffers.values[:n]
+	buffers.valuePtrs = buffers.valuePtrs[:n]
+	for i := range buffers.values {
+		buffers.valuePtrs[i] = &buffers.values[i]
 	}
-	defer rows.Close()
 
+	return buffers
+}
+
+func putScanBuffers(buffers *scanBuffers) {
+	// Drop references to scanned values so the pool does not keep them alive
+	for i := range buffers.values {
+		buffers.values[i] = nil
+	}
+	scanPool.Put(buffers)
+}
+
+// scanRows reads the remaining rows into maps keyed by column name. When max
+// is positive at most max rows are read, and more reports whether another
+// row was available.
+func scanRows(rows *sql.Rows, max int) (result []map[string]interface{}, more bool, err error) {
 	// Dynamically get column names and values
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	var result []map[string]interface{}
-	for rows.Next() {
-		// Prepare a slice for the values
-		values := make([]interface{}, len(columns))
-		valuePtrs := make([]interface{}, len(columns))
+	// The scan destinations are shared by every row: each row's values are
+	// copied into its own map before the next Scan
+	buffers := getScanBuffers(len(columns))
+	defer putScanBuffers(buffers)
+	values, valuePtrs := buffers.values, buffers.valuePtrs
 
-		for i := range values {
-			valuePtrs[i] = &values[i]
+	result = make([]map[string]interface{}, 0)
+	for rows.Next() {
+		if max > 0 && len(result) == max {
+			more = true
+			break
 		}
 
 		// Scan the row into the value pointers
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		// Create a map for the row
-		row := make(map[string]interface{})
+		row := make(map[string]interface{}, len(columns))
 		for i, col := range columns {
 			if b, ok := values[i].([]byte); ok { // Check if the value is a byte slice
 				row[col] = string(b) // Convert byte slice to string
@@ -281,54 +579,89 @@ func (qb *QueryBuilder) Get() ([]map[string]interface{}, error) {
 		result = append(result, row)
 	}
 
-	return result, nil
+	// Surface errors that ended the iteration early (e.g. a dropped connection)
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	return result, more, nil
 }
 
 func (qb *QueryBuilder) Rows() ([]map[string]interface{}, error) {
 	return qb.Get()
 }
 
-// First fetches the first row of the result set.
-func (qb *QueryBuilder) First() (map[string]interface{}, error) {
-	query, params := qb.Build()
-	row := DBConnection.QueryRow(query, params...)
-
-	// Dynamically get column names and values
-	columns, err := DBConnection.Query(query, params...) // Corrected to handle the error
+// GetKeyedBy fetches multiple rows indexed by the value of the given column.
+// When several rows share a key, the last one wins.
+func (qb *QueryBuilder) GetKeyedBy(column string) (map[string]map[string]interface{}, error) {
+	rows, err := qb.Get()
 	if err != nil {
 		return nil, err
 	}
-	defer columns.Close()
 
-	cols, err := columns.Columns() // Get column names
-	if err != nil {
-		return nil, err
+	result := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		key, err := rowKey(row, column)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = row
 	}
 
-	values := make([]interface{}, len(cols))
-	valuePtrs := make([]interface{}, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
-	}
+	return result, nil
+}
 
-	// Scan the row
-	if err := row.Scan(valuePtrs...); err != nil {
+// GetGroupedBy fetches multiple rows grouped by the value of the given column.
+func (qb *QueryBuilder) GetGroupedBy(column string) (map[string][]map[string]interface{}, error) {
+	rows, err := qb.Get()
+	if err != nil {
 		return nil, err
 	}
 
-	// Create a map for the row
-	result := make(map[string]interface{})
-	for i, col := range cols {
-		if b, ok := values[i].([]byte); ok { // Check if the value is a byte slice
-			result[col] = string(b) // Convert byte slice to string
-		} else {
-			result[col] = values[i] // Otherwise, use the original value
+	result := make(map[string][]map[string]interface{})
+	for _, row := range rows {
+		key, err := rowKey(row, column)
+		if err != nil {
+			return nil, err
 		}
+		result[key] = append(result[key], row)
 	}
 
 	return result, nil
 }
 
+// rowKey converts the value of column in row to a map key.
+func rowKey(row map[string]interface{}, column string) (string, error) {
+	value, ok := row[column]
+	if !ok {
+		return "", fmt.Errorf("column %s not found in result set", column)
+	}
+	if value == nil {
+		return "", nil
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// First fetches the first row of the result set.
+// The query is executed once with LIMIT 1; the builder's own limit is left untouched.
+func (qb *QueryBuilder) First() (map[string]interface{}, error) {
+	limit := qb.limit
+	qb.limit = 1
+	query, params := qb.Build()
+	qb.limit = limit
+
+	result, err := qb.fetch(query, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, ErrNoRows
+	}
+
+	return result[0], nil
+}
+
 func (qb *QueryBuilder) Row() (map[string]interface{}, error) {
 	return qb.First()
 }
@@ -336,10 +669,10 @@ func (qb *QueryBuilder) Row() (map[string]interface{}, error) {
 func (qb *QueryBuilder) Count() (int, error) {
 	// Modify query to count rows
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS count_query", qb.BuildSelectQuery())
-	params := qb.parameters
+	params := qb.boundParams()
 
 	var count int
-	err := DBConnection.QueryRow(countQuery, params...).Scan(&count)
+	err := qb.scanOne(countQuery, params, &count)
 	if err != nil {
 		return 0, fmt.Errorf("Error counting rows: %v", err)
 	}
@@ -351,7 +684,7 @@ func (qb *QueryBuilder) Sum(column string) (float64, error) {
 	qb.columns = []string{"SUM(" + column + ")"}
 	query, params := qb.Build()
 	var sumValue float64
-	err := DBConnection.QueryRow(query, params...).Scan(&sumValue)
+	err := qb.scanOne(query, params, &sumValue)
 
 	return sumValue, err
 }
@@ -360,7 +693,7 @@ func (qb *QueryBuilder) Max(column string) (float64, error) {
 	qb.columns = []string{"MAX(" + column + ")"}
 	query, params := qb.Build()
 	var maxValue float64
-	err := DBConnection.QueryRow(query, params...).Scan(&maxValue)
+	err := qb.scanOne(query, params, &maxValue)
 	return maxValue, err
 }
 
@@ -368,7 +701,7 @@ func (qb *QueryBuilder) Min(column string) (float64, error) {
 	qb.columns = []string{"MIN(" + column + ")"}
 	query, params := qb.Build()
 	var minValue float64
-	err := DBConnection.QueryRow(query, params...).Scan(&minValue)
+	err := qb.scanOne(query, params, &minValue)
 
 	return minValue, err
 }
@@ -377,12 +710,21 @@ func (qb *QueryBuilder) Avg(column string) (float64, error) {
 	qb.columns = []string{"AVG(" + column + ")"}
 	query, params := qb.Build()
 	var avgValue float64
-	err := DBConnection.QueryRow(query, params...).Scan(&avgValue)
+	err := qb.scanOne(query, params, &avgValue)
 
 	return avgValue, err
 }
 
 func (qb *QueryBuilder) Insert(data map[string]interface{}) (sql.Result, error) {
+	if err := qb.assignKey(data); err != nil {
+		return nil, err
+	}
+
+	data, err := qb.prepareWrite("insert", data)
+	if err != nil {
+		return nil, err
+	}
+
 	columns := make([]string, 0, len(data))
 	placeholders := make([]string, 0, len(data))
 	params := make([]interface{}, 0, len(data))
@@ -395,7 +737,7 @@ func (qb *QueryBuilder) Insert(data map[string]interface{}) (sql.Result, error)
 
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qb.table, strings.Join(columns, ","), strings.Join(placeholders, ","))
 
-	return DBConnection.Exec(query, params...)
+	return qb.execWrite("insert", query, params, columns, []map[string]interface{}{data})
 }
 
 func (qb *QueryBuilder) BulkInsert(data []map[string]interface{}) (sql.Result, error) {
@@ -403,6 +745,19 @@ func (qb *QueryBuilder) BulkInsert(data []map[string]interface{}) (sql.Result, e
 		return nil, fmt.Errorf("no data to insert")
 	}
 
+	prepared := make([]map[string]interface{}, 0, len(data))
+	for _, row := range data {
+		if err := qb.assignKey(row); err != nil {
+			return nil, err
+		}
+		row, err := qb.prepareWrite("insert", row)
+		if err != nil {
+			return nil, err
+		}
+		prepared = append(prepared, row)
+	}
+	data = prepared
+
 	columns := make([]string, 0)
 	for column := range data[0] {
 		columns = append(columns, column)
@@ -410,48 +765,68 @@ func (qb *QueryBuilder) BulkInsert(data []map[string]interface{}) (sql.Result, e
 
 	values := make([]string, 0)
 	params := make([]interface{}, 0)
+	paramColumns := make([]string, 0)
 
 	for _, row := range data {
 		placeholders := make([]string, len(row))
 		for i, column := range columns {
 			placeholders[i] = "?"
 			params = append(params, row[column])
+			paramColumns = append(paramColumns, column)
 		}
 		values = append(values, fmt.Sprintf("(%s)", strings.Join(placeholders, ",")))
 	}
 
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", qb.table, strings.Join(columns, ","), strings.Join(values, ","))
 
-	return DBConnection.Exec(query, params...)
+	return qb.execWrite("insert", query, params, paramColumns, data)
 }
 
+// Update sets the columns of data on the matching rows. It fails with
+// ErrNoConditions when the builder has no conditions of its own (implicit
+// ones such as tenant scoping or global filters don't count).
 func (qb *QueryBuilder) Update(data map[string]interface{}) (sql.Result, error) {
+	qb.applyImplicit()
+	if !qb.conditioned && len(qb.where) == qb.implicitWhere {
+		return nil, fmt.Errorf("%w on %s", ErrNoConditions, qb.table)
+	}
+
+	return qb.UpdateAll(data)
+}
+
+// UpdateAll is Update without the check for conditions, for statements
+// meant to update every (implicitly scoped) row of the table.
+func (qb *QueryBuilder) UpdateAll(data map[string]interface{}) (sql.Result, error) {
+	data, err := qb.prepareWrite("update", data)
+	if err != nil {
+		return nil, err
+	}
+
 	setClauses := make([]string, 0)
 	params := make([]interface{}, 0)
+	paramColumns := make([]string, 0)
 
 	for column, value := range data {
 		setClauses = append(setClauses, fmt.Sprintf("%s = ?", column))
 		params = append(params, value)
+		paramColumns = append(paramColumns, column)
 	}
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", qb.table, strings.Join(setClauses, ","), strings.Join(qb.where, " AND "))
+	query := fmt.Sprintf("UPDATE %s SET %s%s", qb.table, strings.Join(setClauses, ","), qb.whereClause())
+	params = append(params, qb.parameters...)
+	paramColumns = append(paramColumns, qb.paramColumns...)
 
-	return DBConnection.Exec(query, params...)
+	return qb.execWrite("update", query, params, paramColumns, []map[string]interface{}{data})
 }
 
 func (qb *QueryBuilder) Delete() (sql.Result, error) {
-	query := fmt.Sprintf("DELETE FROM %s", qb.table)
+	query := fmt.Sprintf("DELETE FROM %s", qb.from())
 
 	// Add WHERE clause if exists
-	if len(qb.where) > 0 {
-		query += " WHERE " + strings.Join(qb.where, " AND ")
-	}
-
-	// Print the query for debugging
-	qb.PrintQuery()
+	query += qb.whereClause()
 
 	// Execute the query with the arguments
-	return DBConnection.Exec(query, qb.parameters...)
+	return qb.execWrite("delete", query, qb.parameters, qb.paramColumns, nil)
 }
 
 func TransStart(DBConnection *sql.DB) (*sql.Tx, error) {
@@ -467,7 +842,11 @@ func TransRollback(tx *sql.Tx) error {
 }
 
 // PrintQuery prints the built raw SQL query and its parameters.
+// Parameters bound against masked columns are redacted.
 func (qb *QueryBuilder) PrintQuery() {
 	query, params := qb.Build()
-	fmt.Println(query, params)
+	fmt.Println(query, qb.db.maskParams(params, qb.boundColumns()))
+	for _, origin := range qb.Origins() {
+		fmt.Println("\t" + origin)
+	}
 }
@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// DeleteWithDependents deletes the matching rows together with the rows that
+// reference them, for schemas without ON DELETE CASCADE. dependents maps each
+// child table to its column holding the parent's primary key:
+//
+//	db.Table("users").WhereKey(7).DeleteWithDependents(map[string]string{
+//		"orders":   "user_id",
+//		"sessions": "user_id",
+//	})
+//
+// Child rows are deleted first, then the parent rows, all in one transaction.
+// The result is that of the parent delete.
+func (qb *QueryBuilder) DeleteWithDependents(dependents map[string]string) (sql.Result, error) {
+	children := make([]string, 0, len(dependents))
+	for child, column := range dependents {
+		if !utils.IsValidIdentifier(child) {
+			qb.setError(fmt.Errorf("invalid dependent table: %q", child))
+		}
+		if !utils.IsValidIdentifier(column) {
+			qb.setError(fmt.Errorf("invalid dependent column: %q", column))
+		}
+		children = append(children, child)
+	}
+	sort.Strings(children)
+
+	key, err := qb.primaryKeyColumn()
+	if err != nil {
+		return nil, err
+	}
+	where := qb.whereClause()
+	parents := fmt.Sprintf("SELECT %s FROM %s%s", key, qb.from(), where)
+
+	var result sql.Result
+	err = qb.inTransaction(func() error {
+		for _, child := range children {
+			query := fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", child, dependents[child], parents)
+			if _, err := qb.exec(query, qb.parameters, qb.paramColumns); err != nil {
+				return fmt.Errorf("delete dependents from %s: %w", child, err)
+			}
+		}
+
+		var err error
+		result, err = qb.execWrite("delete", "DELETE FROM "+qb.from()+where, qb.parameters, qb.paramColumns, nil)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package builder
+
+import "encoding/json"
+
+// Collection wraps a result set with helpers for quick post-processing.
+type Collection []map[string]interface{}
+
+// Collect fetches multiple rows and returns them as a Collection.
+func (qb *QueryBuilder) Collect() (Collection, error) {
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return Collection(rows), nil
+}
+
+// Map returns a new Collection holding the result of fn for every row.
+func (c Collection) Map(fn func(row map[string]interface{}) map[string]interface{}) Collection {
+	result := make(Collection, 0, len(c))
+	for _, row := range c {
+		result = append(result, fn(row))
+	}
+
+	return result
+}
+
+// Filter returns a new Collection holding the rows for which fn returns true.
+func (c Collection) Filter(fn func(row map[string]interface{}) bool) Collection {
+	result := make(Collection, 0)
+	for _, row := range c {
+		if fn(row) {
+			result = append(result, row)
+		}
+	}
+
+	return result
+}
+
+// Pluck returns the values of a single column, in row order.
+func (c Collection) Pluck(column string) []interface{} {
+	values := make([]interface{}, 0, len(c))
+	for _, row := range c {
+		values = append(values, row[column])
+	}
+
+	return values
+}
+
+// Chunk splits the Collection into consecutive pieces of at most size rows.
+func (c Collection) Chunk(size int) []Collection {
+	if size <= 0 {
+		return nil
+	}
+
+	chunks := make([]Collection, 0, (len(c)+size-1)/size)
+	for start := 0; start < len(c); start += size {
+		end := start + size
+		if end > len(c) {
+			end = len(c)
+		}
+		chunks = append(chunks, c[start:end])
+	}
+
+	return chunks
+}
+
+// First returns the first row, or nil when the Collection is empty.
+func (c Collection) First() map[string]interface{} {
+	if len(c) == 0 {
+		return nil
+	}
+
+	return c[0]
+}
+
+// ToJSON encodes the Collection as a JSON array.
+func (c Collection) ToJSON() ([]byte, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]map[string]interface{}(c))
+}
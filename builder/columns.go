@@ -0,0 +1,50 @@
+package builder
+
+// ColumnName is a column name with condition helpers, as emitted per table by
+// the qbgen generator, so a misspelt column fails to compile instead of
+// failing at run time:
+//
+//	db.Table(users.Table).Apply(users.Status.Eq("active"), users.Age.Gte(18))
+//
+// Each helper returns a one-condition Fragment.
+type ColumnName string
+
+// String returns the column name.
+func (c ColumnName) String() string { return string(c) }
+
+// Eq matches column = value (IS NULL for nil).
+func (c ColumnName) Eq(value interface{}) *Fragment { return c.where("=", value) }
+
+// Neq matches column != value (IS NOT NULL for nil).
+func (c ColumnName) Neq(value interface{}) *Fragment { return c.where("!=", value) }
+
+// Gt matches column > value.
+func (c ColumnName) Gt(value interface{}) *Fragment { return c.where(">", value) }
+
+// Gte matches column >= value.
+func (c ColumnName) Gte(value interface{}) *Fragment { return c.where(">=", value) }
+
+// Lt matches column < value.
+func (c ColumnName) Lt(value interface{}) *Fragment { return c.where("<", value) }
+
+// Lte matches column <= value.
+func (c ColumnName) Lte(value interface{}) *Fragment { return c.where("<=", value) }
+
+// Like matches column LIKE pattern.
+func (c ColumnName) Like(pattern string) *Fragment {
+	return NewFragment(func(qb *QueryBuilder) { qb.WhereLike(string(c), pattern) })
+}
+
+// In matches column IN (values...).
+func (c ColumnName) In(values ...interface{}) *Fragment {
+	return NewFragment(func(qb *QueryBuilder) { qb.WhereIn(string(c), values) })
+}
+
+// IsNull matches column IS NULL.
+func (c ColumnName) IsNull() *Fragment {
+	return NewFragment(func(qb *QueryBuilder) { qb.WhereNull(string(c)) })
+}
+
+func (c ColumnName) where(operator string, value interface{}) *Fragment {
+	return NewFragment(func(qb *QueryBuilder) { qb.Where(string(c), operator, value) })
+}
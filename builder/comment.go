@@ -0,0 +1,115 @@
+package builder
+
+import (
+	"context"
+	"strings"
+)
+
+var commentSanitizer = strings.NewReplacer("/*", "", "*/", "", "\n", " ", "\r", " ")
+
+// sanitizeComment removes comment delimiters until none are left: a single
+// replacer pass turns "**//" into "*/", which would end the comment early.
+func sanitizeComment(comment string) string {
+	for {
+		cleaned := commentSanitizer.Replace(comment)
+		if cleaned == comment {
+			return strings.TrimSpace(cleaned)
+		}
+		comment = cleaned
+	}
+}
+
+// Comment tags the query with a /* ... */ SQL comment (sqlcommenter style),
+// e.g. qb.Comment("service=checkout, handler=ListOrders"), so it can be
+// attributed in the slow query log and performance_schema.
+func (qb *QueryBuilder) Comment(comment string) *QueryBuilder {
+	comment = sanitizeComment(comment)
+	if comment != "" {
+		qb.comments = append(qb.comments, comment)
+	}
+
+	return qb
+}
+
+// CommentFromContext adds the tag fn derives from the query's context, if
+// any, to the comment of every query, linking slow query log entries to the
+// request or trace that ran them:
+//
+//	db.CommentFromContext(func(ctx context.Context) string {
+//		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+//			return "traceparent=" + span.TraceID().String()
+//		}
+//		return ""
+//	})
+//
+// TraceComment covers the IDs stored with WithTraceID and WithRequestID.
+// Context values often come straight from request headers, so the tag keeps
+// only letters, digits and _ . : = , - and drops every other character.
+func (d *DB) CommentFromContext(fn func(ctx context.Context) string) *DB {
+	d.contextComments = append(d.contextComments, fn)
+
+	return d
+}
+
+type traceIDKey struct{}
+
+type requestIDKey struct{}
+
+// WithTraceID returns a context carrying a trace ID for TraceComment.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// WithRequestID returns a context carrying a request ID for TraceComment.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// TraceComment renders the IDs stored with WithTraceID and WithRequestID as
+// "trace_id=...,request_id=...", for CommentFromContext. IDs keep only
+// letters, digits and _ . : -, so a forged header cannot add tags.
+func TraceComment(ctx context.Context) string {
+	var tags []string
+	if id, _ := ctx.Value(traceIDKey{}).(string); safeTag(id, "") != "" {
+		tags = append(tags, "trace_id="+safeTag(id, ""))
+	}
+	if id, _ := ctx.Value(requestIDKey{}).(string); safeTag(id, "") != "" {
+		tags = append(tags, "request_id="+safeTag(id, ""))
+	}
+
+	return strings.Join(tags, ",")
+}
+
+// safeTag keeps the letters, digits, _ . : - and the extra characters of
+// tag. The result can never close a comment or start a new statement.
+func safeTag(tag, extra string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("_.:-", r), strings.ContainsRune(extra, r):
+			return r
+		}
+		return -1
+	}, tag)
+}
+
+// withComment prepends the builder's comment tags, and those derived from its
+// context, to query.
+func (qb *QueryBuilder) withComment(query string) string {
+	comments := qb.comments
+	if len(qb.db.contextComments) > 0 {
+		ctx := qb.context()
+		comments = append([]string(nil), comments...)
+		for _, fn := range qb.db.contextComments {
+			if tag := safeTag(fn(ctx), "=,"); tag != "" {
+				comments = append(comments, tag)
+			}
+		}
+	}
+	if len(comments) == 0 {
+		return query
+	}
+
+	return "/* " + strings.Join(comments, ", ") + " */ " + query
+}
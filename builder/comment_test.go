@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCommentCannotCloseEarly(t *testing.T) {
+	for _, tag := range []string{"**//", "*/ DROP TABLE users -- ", "/**/*/", "a*//*b", "x\n*/--"} {
+		query := testDB(t).Table("users").Comment(tag).withComment("SELECT 1")
+		body := strings.TrimPrefix(query, "/* ")
+		if end := strings.Index(body, "*/"); end >= 0 && !strings.HasPrefix(body[end:], "*/ SELECT 1") {
+			t.Errorf("Comment(%q): comment closes early in %q", tag, query)
+		}
+		if strings.Count(query, "*/") > 1 || strings.Count(query, "/*") > 1 {
+			t.Errorf("Comment(%q): delimiters left in %q", tag, query)
+		}
+	}
+}
+
+func TestSanitizeComment(t *testing.T) {
+	tests := map[string]string{
+		"service=checkout": "service=checkout",
+		"**//":             "",
+		"a/*b*/c":          "abc",
+		"line\nbreak":      "line break",
+		"path=/api/users":  "path=/api/users",
+	}
+	for in, want := range tests {
+		if got := sanitizeComment(in); got != want {
+			t.Errorf("sanitizeComment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestContextCommentsAreRestricted(t *testing.T) {
+	d := testDB(t).CommentFromContext(TraceComment).CommentFromContext(func(ctx context.Context) string {
+		return "tenant=acme, **// DELETE FROM users -- "
+	})
+	ctx := WithRequestID(WithTraceID(context.Background(), "abc-123"), "**// DELETE FROM users -- ")
+
+	query := d.Table("users").WithContext(ctx).withComment("SELECT 1")
+	want := "/* trace_id=abc-123,request_id=DELETEFROMusers--, tenant=acme,DELETEFROMusers-- */ SELECT 1"
+	if query != want {
+		t.Fatalf("got  %q\nwant %q", query, want)
+	}
+}
@@ -0,0 +1,79 @@
+package builder
+
+import "context"
+
+type scopesKey struct{}
+
+type localeKey struct{}
+
+// WithDefaultScopes returns a context carrying scopes that builders started
+// with FromContext apply, after any scopes already in ctx. Middleware uses it
+// to stash request-wide filters once instead of passing them to every
+// handler:
+//
+//	ctx = builder.WithDefaultScopes(ctx, func(qb *builder.QueryBuilder) *builder.QueryBuilder {
+//		if qb.TableName() == "articles" {
+//			qb.Where("locale", "=", builder.LocaleFromContext(qb.Context()))
+//		}
+//		return qb
+//	})
+func WithDefaultScopes(ctx context.Context, scopes ...ScopeFunc) context.Context {
+	existing := scopesFromContext(ctx)
+	combined := make([]ScopeFunc, 0, len(existing)+len(scopes))
+	combined = append(combined, existing...)
+
+	return context.WithValue(ctx, scopesKey{}, append(combined, scopes...))
+}
+
+func scopesFromContext(ctx context.Context) []ScopeFunc {
+	scopes, _ := ctx.Value(scopesKey{}).([]ScopeFunc)
+
+	return scopes
+}
+
+// WithLocale returns a context carrying the request locale, for default
+// scopes to read.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored by WithLocale, or "".
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+
+	return locale
+}
+
+// FromContext runs the builder under ctx, like WithContext, and applies the
+// default scopes stored in ctx with WithDefaultScopes when the query is first
+// rendered. The builder's own conditions are grouped first, so an OrWhere
+// cannot escape them. The tenant (WithTenant) and audit actor (WithActor) in
+// ctx apply as with WithContext.
+func (qb *QueryBuilder) FromContext(ctx context.Context) *QueryBuilder {
+	qb.contextScopes = scopesFromContext(ctx)
+
+	return qb.WithContext(ctx)
+}
+
+// Context returns the context the builder runs under.
+func (qb *QueryBuilder) Context() context.Context {
+	return qb.context()
+}
+
+// TableName returns the table the builder queries.
+func (qb *QueryBuilder) TableName() string {
+	return qb.table
+}
+
+func (qb *QueryBuilder) applyContextScopes() {
+	if len(qb.contextScopes) == 0 {
+		return
+	}
+
+	scopes := qb.contextScopes
+	qb.contextScopes = nil
+	qb.groupWhere()
+	for _, fn := range scopes {
+		fn(qb)
+	}
+}
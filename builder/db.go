@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/db"
+)
+
+// DB is a handle on a connection pool that query builders run against.
+// The pool is owned by the caller: DB never opens or closes it.
+type DB struct {
+	conn              *sql.DB
+	connections       map[string]Runner
+	slowQueryHooks    []slowQueryHook
+	auditTables       map[string]bool
+	auditTable        string
+	sequenceTable     string
+	encrypted         map[string]map[string]Cipher
+	masks             map[string]func(interface{}) interface{}
+	restrictedColumns map[string][]string
+	validators        map[string][]Validator
+	primaryKeys       map[string]primaryKey
+	rowLimit          rowLimit
+	limitCaps         limitCaps
+	readOnly          bool
+	lint              bool
+	debug             bool
+	scopes            map[string]ScopeFunc
+	savedQueries      map[string]savedQuery
+	contextComments   []func(ctx context.Context) string
+	tenantTables      map[string]string
+	globalFilters     map[string][]func(*QueryBuilder)
+	defaultOrders     map[string]string
+	sessionVars       []sessionVar
+	sqlDialect        Dialect
+
+	paramConverters []ParamConverter
+	scanners        map[string]ScanFunc
+	bindLocation    *time.Location
+	bindTimeFormat  string
+
+	deadlockDiagnostics bool
+	txWatch             *txWatch
+
+	versionMu sync.Mutex
+	version   *Version
+
+	schemaMu    sync.Mutex
+	enumTables  map[string]bool
+	enumCache   map[string]map[string]enumColumn
+	jsonTables  map[string]bool
+	jsonColumns map[string]map[string]bool
+	jsonCache   map[string]map[string]bool
+
+	singleflight flightGroup
+}
+
+// NewDB wraps an existing *sql.DB.
+func NewDB(conn *sql.DB) *DB {
+	return &DB{conn: conn}
+}
+
+// Conn returns the underlying connection pool.
+func (d *DB) Conn() *sql.DB {
+	return d.conn
+}
+
+// Table starts a new query against table.
+func (d *DB) Table(table string) *QueryBuilder {
+	db.IsConnected(d.conn)
+
+	return &QueryBuilder{
+		db:     d,
+		runner: d.conn,
+		table:  table,
+		limit:  -1,
+		offset: -1,
+	}
+}
+
+// AddConnection registers a named connection (a replica pool, a dedicated
+// *sql.Conn, ...) that builders can target with UseConnection.
+func (d *DB) AddConnection(name string, conn Runner) *DB {
+	if d.connections == nil {
+		d.connections = make(map[string]Runner)
+	}
+	d.connections[name] = conn
+
+	return d
+}
@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// maxDeadlockReport bounds the report attached to a DeadlockError.
+const maxDeadlockReport = 8 << 10
+
+// DeadlockError is returned instead of the driver's error for a deadlock
+// when DiagnoseDeadlocks is on. Report holds the LATEST DETECTED DEADLOCK
+// section of SHOW ENGINE INNODB STATUS: the two transactions, the locks they
+// held and waited for, and the one the server rolled back.
+type DeadlockError struct {
+	Err    error
+	Report string
+}
+
+func (e *DeadlockError) Error() string {
+	if e.Report == "" {
+		return e.Err.Error()
+	}
+
+	return e.Err.Error() + "\n" + e.Report
+}
+
+func (e *DeadlockError) Unwrap() error {
+	return e.Err
+}
+
+// IsDeadlock reports whether err is a MySQL deadlock (error 1213), after
+// which the transaction has been rolled back and can be retried.
+func IsDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1213
+}
+
+// DiagnoseDeadlocks makes statements failing with a deadlock return a
+// *DeadlockError carrying the server's report on it. Reading the report needs
+// the PROCESS privilege; without it the error says why the report is missing.
+// The report is as of the moment after the failure, so under heavy contention
+// it may already describe a later deadlock.
+func (d *DB) DiagnoseDeadlocks(enabled bool) *DB {
+	d.deadlockDiagnostics = enabled
+
+	return d
+}
+
+// diagnose attaches the deadlock report to err when asked to.
+func (qb *QueryBuilder) diagnose(err error) error {
+	if err == nil || !qb.db.deadlockDiagnostics || !IsDeadlock(err) {
+		return err
+	}
+
+	report, rerr := qb.deadlockReport()
+	if rerr != nil {
+		report = fmt.Sprintf("(deadlock report unavailable: %v)", rerr)
+	}
+
+	return &DeadlockError{Err: err, Report: report}
+}
+
+// deadlockReport reads the latest deadlock from the InnoDB monitor, on the
+// pool since the failed transaction's connection may be unusable.
+func (qb *QueryBuilder) deadlockReport() (string, error) {
+	var kind, name, status string
+	var runner Runner = qb.db.conn
+	if qb.db.conn == nil {
+		runner = qb.runner
+	}
+	diag := &QueryBuilder{db: qb.db, runner: runner, ctx: qb.ctx}
+	if err := diag.scanFirst("SHOW ENGINE INNODB STATUS", nil, &kind, &name, &status); err != nil {
+		return "", err
+	}
+
+	return latestDeadlock(status), nil
+}
+
+// latestDeadlock cuts the LATEST DETECTED DEADLOCK section out of the InnoDB
+// monitor output.
+func latestDeadlock(status string) string {
+	const heading = "LATEST DETECTED DEADLOCK"
+	start := strings.Index(status, heading)
+	if start < 0 {
+		return "(no deadlock recorded by the server)"
+	}
+	section := status[start:]
+
+	// The section ends at the next heading, framed by lines of dashes
+	if end := strings.Index(section, "\n------------\nTRANSACTIONS"); end >= 0 {
+		section = section[:end]
+	}
+	if len(section) > maxDeadlockReport {
+		section = section[:maxDeadlockReport] + "\n... (truncated)"
+	}
+
+	return strings.TrimSpace(section)
+}
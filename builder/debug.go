@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// clauseOrigin records where a clause or parameter was added.
+type clauseOrigin struct {
+	clause string
+	caller string
+}
+
+// Debug records the file:line of the code adding each parameter, join and
+// having clause, and of the call that produced a builder error. Errors from
+// the builder (misuse, placeholder mismatches, SQL lint) then name those
+// locations, and slow query reports carry them in QueryInfo.Origins. It costs
+// a stack walk per clause, so enable it while tracking a problem down.
+func (qb *QueryBuilder) Debug() *QueryBuilder {
+	qb.debug = true
+
+	return qb
+}
+
+// Debug enables Debug for every builder of d.
+func (d *DB) Debug(enabled bool) *DB {
+	d.debug = enabled
+
+	return d
+}
+
+func (qb *QueryBuilder) debugging() bool {
+	return qb.debug || qb.db.debug
+}
+
+// traceClause records the caller adding a clause, described by format and
+// args, in debug mode.
+func (qb *QueryBuilder) traceClause(format string, args ...interface{}) {
+	if !qb.debugging() {
+		return
+	}
+
+	qb.origins = append(qb.origins, clauseOrigin{clause: fmt.Sprintf(format, args...), caller: callerOutsidePackage()})
+}
+
+// Origins returns the recorded clause locations, "clause at file:line", in
+// the order they were added. It is empty unless Debug is on.
+func (qb *QueryBuilder) Origins() []string {
+	if len(qb.origins) == 0 {
+		return nil
+	}
+
+	origins := make([]string, len(qb.origins))
+	for i, origin := range qb.origins {
+		origins[i] = origin.clause + " at " + origin.caller
+	}
+
+	return origins
+}
+
+// debugError adds the recorded clause locations to err, in debug mode.
+func (qb *QueryBuilder) debugError(err error) error {
+	if err == nil || len(qb.origins) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w\nclauses:\n\t%s", err, strings.Join(qb.Origins(), "\n\t"))
+}
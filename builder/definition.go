@@ -0,0 +1,149 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// Definition is a serializable description of a SELECT query, for saved
+// filters and report definitions. It round-trips through encoding/json and
+// is validated when turned back into a builder with DB.FromDefinition.
+type Definition struct {
+	Table   string      `json:"table"`
+	Columns []string    `json:"columns,omitempty"`
+	Where   []Condition `json:"where,omitempty"`
+	GroupBy []string    `json:"group_by,omitempty"`
+	OrderBy []Order     `json:"order_by,omitempty"`
+	Limit   *int        `json:"limit,omitempty"`
+	Offset  *int        `json:"offset,omitempty"`
+}
+
+// Condition is a single WHERE condition. Operator is one of the comparison
+// operators accepted by Where, or "in", "not in", "like", "not like",
+// "between" (Value holds two items) or "null" (Value is ignored).
+type Condition struct {
+	Column   string      `json:"column"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+	Or       bool        `json:"or,omitempty"`
+}
+
+// Order is a single ORDER BY term.
+type Order struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc,omitempty"`
+}
+
+// FromDefinition builds a query from def. Table and column names must be
+// plain identifiers and operators must be whitelisted, so definitions from
+// untrusted storage cannot inject SQL.
+func (d *DB) FromDefinition(def Definition) (*QueryBuilder, error) {
+	if !utils.IsValidIdentifier(def.Table) {
+		return nil, fmt.Errorf("invalid table name: %q", def.Table)
+	}
+
+	qb := d.Table(def.Table)
+
+	for _, column := range def.Columns {
+		if !utils.IsValidIdentifier(column) {
+			return nil, fmt.Errorf("invalid column name: %q", column)
+		}
+	}
+	qb.Select(def.Columns...)
+
+	for _, condition := range def.Where {
+		if err := qb.applyCondition(condition); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, column := range def.GroupBy {
+		if !utils.IsValidIdentifier(column) {
+			return nil, fmt.Errorf("invalid group by column: %q", column)
+		}
+	}
+	if len(def.GroupBy) > 0 {
+		qb.GroupBy(def.GroupBy...)
+	}
+
+	orders := make([]string, 0, len(def.OrderBy))
+	for _, order := range def.OrderBy {
+		if !utils.IsValidIdentifier(order.Column) {
+			return nil, fmt.Errorf("invalid order by column: %q", order.Column)
+		}
+		if order.Desc {
+			orders = append(orders, order.Column+" DESC")
+		} else {
+			orders = append(orders, order.Column+" ASC")
+		}
+	}
+	if len(orders) > 0 {
+		qb.OrderBy(strings.Join(orders, ", "))
+	}
+
+	if def.Limit != nil {
+		qb.Limit(*def.Limit)
+	}
+	if def.Offset != nil {
+		qb.Offset(*def.Offset)
+	}
+
+	return qb, nil
+}
+
+func (qb *QueryBuilder) applyCondition(c Condition) error {
+	if !utils.IsValidIdentifier(c.Column) {
+		return fmt.Errorf("invalid where column: %q", c.Column)
+	}
+
+	operator := strings.ToLower(strings.TrimSpace(c.Operator))
+	if utils.AllowedOperators[operator] {
+		if c.Or {
+			qb.OrWhere(c.Column, operator, c.Value)
+		} else {
+			qb.Where(c.Column, operator, c.Value)
+		}
+		return nil
+	}
+
+	if c.Or {
+		return fmt.Errorf("operator %q on %s cannot be combined with or", c.Operator, c.Column)
+	}
+
+	switch operator {
+	case "in", "not in":
+		values, ok := toSlice(c.Value)
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("operator %q on %s needs a non-empty list", c.Operator, c.Column)
+		}
+		if operator == "in" {
+			qb.WhereIn(c.Column, values)
+		} else {
+			qb.WhereNotIn(c.Column, values)
+		}
+	case "like", "not like":
+		value, ok := c.Value.(string)
+		if !ok {
+			return fmt.Errorf("operator %q on %s needs a string", c.Operator, c.Column)
+		}
+		if operator == "like" {
+			qb.WhereLike(c.Column, value)
+		} else {
+			qb.WhereNotLike(c.Column, value)
+		}
+	case "between":
+		values, ok := toSlice(c.Value)
+		if !ok || len(values) != 2 {
+			return fmt.Errorf("operator %q on %s needs two values", c.Operator, c.Column)
+		}
+		qb.WhereBetween(c.Column, values[0], values[1])
+	case "null":
+		qb.WhereNull(c.Column)
+	default:
+		return fmt.Errorf("operator %q is not allowed", c.Operator)
+	}
+
+	return nil
+}
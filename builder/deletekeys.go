@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// DeleteByKeys deletes the rows whose column is one of keys, chunkSize keys
+// per DELETE ... WHERE column IN (...), and returns the number of rows
+// deleted. Bounded statements keep row locks short and binlog events small
+// on big purges. The builder's own conditions apply to every chunk. Each
+// chunk commits on its own, so a failure leaves the earlier chunks deleted;
+// the count returned covers them.
+func (qb *QueryBuilder) DeleteByKeys(column string, keys []interface{}, chunkSize int) (int64, error) {
+	return qb.DeleteByKeysPaced(column, keys, chunkSize, 0)
+}
+
+// DeleteByKeysPaced is DeleteByKeys sleeping pause between chunks, to leave
+// room for replication and other traffic. The builder's context cancels the
+// pause.
+func (qb *QueryBuilder) DeleteByKeysPaced(column string, keys []interface{}, chunkSize int, pause time.Duration) (int64, error) {
+	if !utils.IsValidIdentifier(column) {
+		return 0, fmt.Errorf("invalid column name: %q", column)
+	}
+	if chunkSize <= 0 {
+		return 0, fmt.Errorf("delete from %s: invalid chunk size: %d", qb.table, chunkSize)
+	}
+
+	var deleted int64
+	for start := 0; start < len(keys); start += chunkSize {
+		if start > 0 && pause > 0 {
+			if err := sleepContext(qb.context(), pause); err != nil {
+				return deleted, err
+			}
+		}
+
+		end := start + chunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		result, err := qb.clone().WhereIn(column, keys[start:end]).Delete()
+		if err != nil {
+			return deleted, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	return deleted, nil
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,29 @@
+package builder
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestDeleteByKeysPrintsNothing(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	testDB(t).Table("sessions").DeleteByKeys("id", []interface{}{1, 2, 3}, 2)
+	w.Close()
+	os.Stdout = stdout
+
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("DeleteByKeys printed %q", out)
+	}
+}
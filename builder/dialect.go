@@ -0,0 +1,171 @@
+package builder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect renders the parts of a statement that differ between SQL engines.
+// Builders always compose SQL with ? placeholders; the DB's dialect rewrites
+// them when the statement is executed, so the same builder code can run on
+// MySQL, PostgreSQL or SQLite (e.g. SQLite in tests). Features beyond plain
+// CRUD (schema helpers, information_schema reports, locking, ...) stay
+// MySQL-specific.
+type Dialect interface {
+	// Name identifies the dialect, e.g. "mysql".
+	Name() string
+
+	// Placeholder returns the n-th (1-based) parameter placeholder.
+	Placeholder(n int) string
+
+	// QuoteIdentifier quotes a table, column or alias name.
+	QuoteIdentifier(name string) string
+
+	// LimitOffset renders the LIMIT/OFFSET clause with a leading space; a
+	// negative value means unset.
+	LimitOffset(limit, offset int) string
+}
+
+// Built-in dialects for DB.SetDialect.
+var (
+	MySQL    Dialect = mysqlDialect{}
+	Postgres Dialect = postgresDialect{}
+	SQLite   Dialect = sqliteDialect{}
+)
+
+// SetDialect selects the SQL dialect of the DB; MySQL by default.
+func (d *DB) SetDialect(dialect Dialect) *DB {
+	d.sqlDialect = dialect
+
+	return d
+}
+
+// Dialect returns the SQL dialect of the DB.
+func (d *DB) Dialect() Dialect {
+	if d.sqlDialect == nil {
+		return MySQL
+	}
+
+	return d.sqlDialect
+}
+
+// Rebind rewrites the ? placeholders of query, outside quoted strings,
+// identifiers and comments, into the dialect's placeholders, for SQL built
+// with Build or BuildSelectQuery and executed directly.
+func Rebind(dialect Dialect, query string) string {
+	if dialect.Placeholder(1) == "?" {
+		return query
+	}
+
+	var out strings.Builder
+	out.Grow(len(query) + 16)
+	var quote byte
+	n := 0
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(query) {
+				out.WriteByte(c)
+				i++
+				c = query[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				out.WriteString(query[i:])
+				return out.String()
+			}
+			out.WriteString(query[i : i+end+4])
+			i += end + 3
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "-- "):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				out.WriteString(query[i:])
+				return out.String()
+			}
+			out.WriteString(query[i : i+end+1])
+			i += end
+			continue
+		case c == '?':
+			n++
+			out.WriteString(dialect.Placeholder(n))
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
+
+// quoteIdentifier quotes name for the builder's dialect.
+func (qb *QueryBuilder) quoteIdentifier(name string) string {
+	return qb.db.Dialect().QuoteIdentifier(name)
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+func (mysqlDialect) Placeholder(int) string { return "?" }
+
+func (mysqlDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name) }
+
+// LimitOffset uses the largest row count MySQL accepts for an OFFSET without
+// a LIMIT, as the manual suggests.
+func (mysqlDialect) LimitOffset(limit, offset int) string {
+	if limit < 0 && offset >= 0 {
+		return " LIMIT 18446744073709551615 OFFSET " + strconv.Itoa(offset)
+	}
+
+	return limitOffset(limit, offset)
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (postgresDialect) QuoteIdentifier(name string) string { return doubleQuote(name) }
+
+func (postgresDialect) LimitOffset(limit, offset int) string { return limitOffset(limit, offset) }
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Placeholder(int) string { return "?" }
+
+func (sqliteDialect) QuoteIdentifier(name string) string { return doubleQuote(name) }
+
+func (sqliteDialect) LimitOffset(limit, offset int) string {
+	if limit < 0 && offset >= 0 {
+		return " LIMIT -1 OFFSET " + strconv.Itoa(offset)
+	}
+
+	return limitOffset(limit, offset)
+}
+
+// limitOffset renders the standard LIMIT n OFFSET m form.
+func limitOffset(limit, offset int) string {
+	clause := ""
+	if limit >= 0 {
+		clause += " LIMIT " + strconv.Itoa(limit)
+	}
+	if offset >= 0 {
+		clause += " OFFSET " + strconv.Itoa(offset)
+	}
+
+	return clause
+}
+
+func doubleQuote(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
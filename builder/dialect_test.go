@@ -0,0 +1,30 @@
+package builder
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users WHERE id = ? AND name = ?": "SELECT * FROM users WHERE id = $1 AND name = $2",
+		"SELECT '?', \"?\", `?` FROM t WHERE a = ?":     "SELECT '?', \"?\", `?` FROM t WHERE a = $1",
+		"SELECT 'it\\'s ?' FROM t WHERE a = ?":          "SELECT 'it\\'s ?' FROM t WHERE a = $1",
+		"/* why? */ SELECT * FROM t WHERE a = ?":        "/* why? */ SELECT * FROM t WHERE a = $1",
+		"SELECT * FROM t -- really?\nWHERE a = ?":       "SELECT * FROM t -- really?\nWHERE a = $1",
+		"SELECT * FROM t WHERE a = ? -- why?":           "SELECT * FROM t WHERE a = $1 -- why?",
+		"SELECT * FROM t WHERE a = ? /* open?":          "SELECT * FROM t WHERE a = $1 /* open?",
+		"SELECT a-? FROM t":                             "SELECT a-$1 FROM t",
+		"SELECT a/? FROM t":                             "SELECT a/$1 FROM t",
+	}
+	for in, want := range tests {
+		if got := Rebind(Postgres, in); got != want {
+			t.Errorf("Rebind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRebindComment(t *testing.T) {
+	db := testDB(t).SetDialect(Postgres)
+	query := Rebind(db.Dialect(), db.Table("users").Comment("why?").Where("id", "=", 1).withComment("SELECT * FROM users WHERE id = ?"))
+	if want := "/* why? */ SELECT * FROM users WHERE id = $1"; query != want {
+		t.Errorf("got %q, want %q", query, want)
+	}
+}
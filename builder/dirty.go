@@ -0,0 +1,171 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Record is a row loaded with LoadForUpdate that remembers which columns
+// were changed, so SaveChanges writes only those. Updating a few columns of
+// a wide row this way keeps the statement, the binlog entry and the time the
+// row stays locked small.
+type Record struct {
+	qb       *QueryBuilder
+	id       interface{}
+	original map[string]interface{}
+	values   map[string]interface{}
+}
+
+// LoadForUpdate fetches the row with the given primary key (see WhereKey)
+// as a tracked Record, or returns ErrNoRows. Chain ForUpdate inside a
+// transaction to hold the row until SaveChanges.
+func (qb *QueryBuilder) LoadForUpdate(id interface{}) (*Record, error) {
+	base := qb.clone()
+	row, err := qb.FindOrFail(id)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		values[column] = value
+	}
+
+	return &Record{qb: base, id: id, original: row, values: values}, nil
+}
+
+// Get returns the current value of column.
+func (r *Record) Get(column string) interface{} {
+	return r.values[column]
+}
+
+// Values returns a copy of the current values.
+func (r *Record) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(r.values))
+	for column, value := range r.values {
+		values[column] = value
+	}
+
+	return values
+}
+
+// Set changes the value of column. Setting a column back to its loaded value
+// leaves it unchanged.
+func (r *Record) Set(column string, value interface{}) *Record {
+	r.values[column] = value
+
+	return r
+}
+
+// Assign sets the columns named by the qb tags of a struct (the column part
+// of the tag, as used by WhereStruct), including zero values.
+func (r *Record) Assign(src interface{}) error {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fmt.Errorf("Assign expects a struct, got nil %T", src)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("Assign expects a struct, got %T", src)
+	}
+
+	r.assignFields(v)
+
+	return nil
+}
+
+func (r *Record) assignFields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		tag, tagged := field.Tag.Lookup("qb")
+		if !tagged {
+			if field.Anonymous && value.Kind() == reflect.Struct {
+				r.assignFields(value)
+			}
+			continue
+		}
+		if tag == "-" || field.PkgPath != "" {
+			continue
+		}
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			tag = tag[:comma]
+		}
+
+		r.Set(tag, value.Interface())
+	}
+}
+
+// Changes returns the columns whose value differs from the loaded one, with
+// their new values.
+func (r *Record) Changes() map[string]interface{} {
+	changes := make(map[string]interface{})
+	for column, value := range r.values {
+		if old, loaded := r.original[column]; !loaded || !sameValue(old, value) {
+			changes[column] = value
+		}
+	}
+
+	return changes
+}
+
+// Changed returns the names of the changed columns, sorted.
+func (r *Record) Changed() []string {
+	var columns []string
+	for column := range r.Changes() {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// IsDirty reports whether any column was changed.
+func (r *Record) IsDirty() bool {
+	return len(r.Changes()) > 0
+}
+
+// SaveChanges updates the changed columns of the row; with no changes it
+// runs nothing and returns a nil Result. Afterwards the saved values count
+// as loaded.
+func (r *Record) SaveChanges() (sql.Result, error) {
+	changes := r.Changes()
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	result, err := r.qb.clone().UpdateByKey(r.id, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	for column, value := range changes {
+		r.original[column] = value
+	}
+	if columns, values, err := r.qb.keyValues(r.id); err == nil {
+		// follow the row when its key was changed
+		values = append([]interface{}(nil), values...)
+		moved := false
+		for i, column := range columns {
+			if value, ok := changes[column]; ok {
+				values[i], moved = value, true
+			}
+		}
+		if moved {
+			key := make(Key, len(columns))
+			for i, column := range columns {
+				key[column] = values[i]
+			}
+			r.id = key
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// duplicateExamples is the number of example keys returned per duplicate.
+const duplicateExamples = 5
+
+// Duplicate is a set of rows sharing the same values.
+type Duplicate struct {
+	Values     map[string]interface{} // the shared values, by column
+	Count      int64
+	ExampleIDs []string // up to five primary keys of the rows, lowest first
+}
+
+// FindDuplicates returns the value combinations of columns that occur in more
+// than one matching row, most frequent first, with GROUP BY ... HAVING
+// COUNT(*) > 1:
+//
+//	dups, err := db.Table("users").FindDuplicates("email")
+func (qb *QueryBuilder) FindDuplicates(columns ...string) ([]Duplicate, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("find duplicates in %s: no columns given", qb.table)
+	}
+	for _, column := range columns {
+		if !utils.IsValidIdentifier(column) {
+			return nil, fmt.Errorf("invalid column name: %q", column)
+		}
+	}
+
+	key, err := qb.primaryKeyColumn()
+	if err != nil {
+		return nil, fmt.Errorf("find duplicates in %s: example ids need a single-column key: %w", qb.table, err)
+	}
+	qb.columns = append(append([]string{}, columns...),
+		"COUNT(*) AS qb_duplicates",
+		fmt.Sprintf("SUBSTRING_INDEX(GROUP_CONCAT(%s ORDER BY %s), ',', %d) AS qb_examples", key, key, duplicateExamples))
+	qb.GroupBy(columns...)
+	qb.Having("COUNT(*) > 1")
+	qb.OrderBy("qb_duplicates DESC")
+	query, params := qb.Build()
+
+	rows, err := qb.fetch(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]Duplicate, len(rows))
+	for i, row := range rows {
+		d := Duplicate{Values: make(map[string]interface{}, len(columns))}
+		for _, column := range columns {
+			d.Values[column] = row[column[strings.LastIndex(column, ".")+1:]]
+		}
+		d.Count, _ = strconv.ParseInt(fmt.Sprint(row["qb_duplicates"]), 10, 64)
+		if examples, ok := row["qb_examples"].(string); ok && examples != "" {
+			d.ExampleIDs = strings.Split(examples, ",")
+		}
+		duplicates[i] = d
+	}
+
+	return duplicates, nil
+}
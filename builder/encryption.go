@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Cipher encrypts and decrypts column values.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns a Cipher using AES-GCM with a random nonce prepended to
+// each ciphertext. key must be 16, 24 or 32 bytes long.
+func NewAESGCM(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesGCM{aead: aead}, nil
+}
+
+func (c *aesGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *aesGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	return c.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+// EncryptColumns stores the given columns of table encrypted with c. Values are
+// encrypted on Insert/Update and decrypted on Get/First, so the columns should
+// be binary (VARBINARY/BLOB) and cannot be meaningfully filtered on.
+func (d *DB) EncryptColumns(table string, c Cipher, columns ...string) *DB {
+	if d.encrypted == nil {
+		d.encrypted = make(map[string]map[string]Cipher)
+	}
+	if d.encrypted[table] == nil {
+		d.encrypted[table] = make(map[string]Cipher)
+	}
+	for _, column := range columns {
+		d.encrypted[table][column] = c
+	}
+
+	return d
+}
+
+func (qb *QueryBuilder) encryptValues(data map[string]interface{}) error {
+	for column, c := range qb.db.encrypted[qb.table] {
+		value, ok := data[column]
+		if !ok || value == nil {
+			continue
+		}
+
+		var plaintext []byte
+		switch v := value.(type) {
+		case []byte:
+			plaintext = v
+		case string:
+			plaintext = []byte(v)
+		default:
+			plaintext = []byte(fmt.Sprint(v))
+		}
+
+		ciphertext, err := c.Encrypt(plaintext)
+		if err != nil {
+			return fmt.Errorf("error encrypting column %s: %w", column, err)
+		}
+		data[column] = ciphertext
+	}
+
+	return nil
+}
+
+func (qb *QueryBuilder) decryptValues(rows []map[string]interface{}) error {
+	columns := qb.db.encrypted[qb.table]
+	if len(columns) == 0 {
+		return nil
+	}
+
+	for _, row := range rows {
+		for column, c := range columns {
+			// scanRows has already turned []byte values into strings
+			value, ok := row[column].(string)
+			if !ok {
+				continue
+			}
+
+			plaintext, err := c.Decrypt([]byte(value))
+			if err != nil {
+				return fmt.Errorf("error decrypting column %s: %w", column, err)
+			}
+			row[column] = string(plaintext)
+		}
+	}
+
+	return nil
+}
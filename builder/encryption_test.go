@@ -0,0 +1,132 @@
+package builder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testCipher(t *testing.T, key string) Cipher {
+	t.Helper()
+
+	c, err := NewAESGCM([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestAESGCMRoundTrip(t *testing.T) {
+	c := testCipher(t, "0123456789abcdef0123456789abcdef")
+	plaintext := []byte("4111 1111 1111 1111")
+
+	first, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(first, plaintext) {
+		t.Error("ciphertext holds the plaintext")
+	}
+	if bytes.Equal(first, second) {
+		t.Error("two encryptions of the same value are equal; the nonce is not random")
+	}
+
+	for _, ciphertext := range [][]byte{first, second} {
+		got, err := c.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("Decrypt = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestAESGCMRejectsTamperedCiphertext(t *testing.T) {
+	c := testCipher(t, "0123456789abcdef")
+	ciphertext, err := c.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range ciphertext {
+		tampered := append([]byte{}, ciphertext...)
+		tampered[i] ^= 1
+		if _, err := c.Decrypt(tampered); err == nil {
+			t.Fatalf("Decrypt accepted a ciphertext with byte %d flipped", i)
+		}
+	}
+	if _, err := c.Decrypt(ciphertext[:len(ciphertext)-1]); err == nil {
+		t.Error("Decrypt accepted a truncated ciphertext")
+	}
+	if _, err := c.Decrypt(ciphertext[:4]); err == nil {
+		t.Error("Decrypt accepted a ciphertext shorter than the nonce")
+	}
+}
+
+func TestAESGCMRejectsWrongKey(t *testing.T) {
+	ciphertext, err := testCipher(t, "0123456789abcdef").Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testCipher(t, "fedcba9876543210").Decrypt(ciphertext); err == nil {
+		t.Error("Decrypt with another key succeeded")
+	}
+}
+
+func TestNewAESGCMRejectsBadKeySize(t *testing.T) {
+	if _, err := NewAESGCM([]byte("short")); err == nil {
+		t.Error("NewAESGCM accepted a 5-byte key")
+	}
+}
+
+func TestEncryptedColumnsAreTransparent(t *testing.T) {
+	c := testCipher(t, "0123456789abcdef")
+	d := testDB(t).EncryptColumns("users", c, "ssn", "note")
+
+	stored, err := d.Table("users").prepareWrite("insert", map[string]interface{}{
+		"name": "ann",
+		"ssn":  "123-45-6789",
+		"note": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored["name"] != "ann" {
+		t.Errorf("name = %v, want it stored as is", stored["name"])
+	}
+	if stored["note"] != nil {
+		t.Errorf("note = %v, want NULL left unencrypted", stored["note"])
+	}
+	ciphertext, ok := stored["ssn"].([]byte)
+	if !ok || bytes.Contains(ciphertext, []byte("123-45-6789")) {
+		t.Fatalf("ssn = %v, want ciphertext", stored["ssn"])
+	}
+
+	// scanRows hands binary columns over as strings
+	rows := []map[string]interface{}{{"name": "ann", "ssn": string(ciphertext), "note": nil}}
+	if err := d.Table("users").decryptValues(rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows[0]["ssn"] != "123-45-6789" || rows[0]["name"] != "ann" || rows[0]["note"] != nil {
+		t.Errorf("decrypted row = %v", rows[0])
+	}
+
+	other := []map[string]interface{}{{"ssn": string(ciphertext)}}
+	if err := d.Table("accounts").decryptValues(other); err != nil {
+		t.Fatal(err)
+	}
+	if other[0]["ssn"] != string(ciphertext) {
+		t.Error("a table without encrypted columns was decrypted")
+	}
+
+	rows[0]["ssn"] = string(ciphertext[:len(ciphertext)-1])
+	if err := d.Table("users").decryptValues(rows); err == nil {
+		t.Error("decrypting a damaged column succeeded")
+	}
+}
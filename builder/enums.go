@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateEnums makes Insert and Update check values bound to ENUM and SET
+// columns of the given tables against the definitions in information_schema,
+// returning a *ValidationError instead of relying on MySQL's truncation
+// warnings. Definitions are loaded on first use and cached.
+func (d *DB) ValidateEnums(tables ...string) *DB {
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+
+	if d.enumTables == nil {
+		d.enumTables = make(map[string]bool)
+	}
+	for _, table := range tables {
+		d.enumTables[table] = true
+	}
+
+	return d
+}
+
+type enumColumn struct {
+	set     bool
+	members []string
+}
+
+// enumColumns returns the ENUM/SET columns of the builder's table.
+func (qb *QueryBuilder) enumColumns() (map[string]enumColumn, error) {
+	d := qb.db
+	d.schemaMu.Lock()
+	cached, ok := d.enumCache[qb.table]
+	d.schemaMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	rows, err := qb.lookup().query("SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND DATA_TYPE IN ('enum', 'set')", []interface{}{qb.table})
+	if err != nil {
+		return nil, fmt.Errorf("error loading enum columns of %s: %w", qb.table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]enumColumn)
+	for rows.Next() {
+		var name, dataType, columnType string
+		if err := rows.Scan(&name, &dataType, &columnType); err != nil {
+			return nil, err
+		}
+		columns[name] = enumColumn{set: dataType == "set", members: parseEnumMembers(columnType)}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+	if d.enumCache == nil {
+		d.enumCache = make(map[string]map[string]enumColumn)
+	}
+	d.enumCache[qb.table] = columns
+
+	return columns, nil
+}
+
+// parseEnumMembers parses a COLUMN_TYPE, whose members double their quotes:
+//
+//	enum('a','it''s')
+func parseEnumMembers(columnType string) []string {
+	open := strings.Index(columnType, "(")
+	if open < 0 || !strings.HasSuffix(columnType, ")") {
+		return nil
+	}
+	body := columnType[open+1 : len(columnType)-1]
+
+	members := make([]string, 0)
+	var current strings.Builder
+	inQuote := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(body) && body[i+1] == '\'':
+			current.WriteByte('\'')
+			i++
+		case c == '\'':
+			if inQuote {
+				members = append(members, current.String())
+				current.Reset()
+			}
+			inQuote = !inQuote
+		case inQuote:
+			current.WriteByte(c)
+		}
+	}
+
+	return members
+}
+
+func (qb *QueryBuilder) validateEnums(data map[string]interface{}) error {
+	qb.db.schemaMu.Lock()
+	enabled := qb.db.enumTables[qb.table]
+	qb.db.schemaMu.Unlock()
+	if !enabled {
+		return nil
+	}
+
+	columns, err := qb.enumColumns()
+	if err != nil {
+		return err
+	}
+
+	verr := &ValidationError{Table: qb.table}
+	for name, column := range columns {
+		value, ok := data[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		text := fmt.Sprint(value)
+		if b, ok := value.([]byte); ok {
+			text = string(b)
+		}
+
+		values := []string{text}
+		if column.set {
+			values = strings.Split(text, ",")
+			if text == "" {
+				continue
+			}
+		}
+		for _, v := range values {
+			if !column.allows(v) {
+				verr.Add(name, fmt.Sprintf("%q is not one of %s", v, strings.Join(column.members, ", ")))
+				break
+			}
+		}
+	}
+
+	return verr.Err()
+}
+
+// allows compares case-insensitively, as MySQL's default collations do.
+func (c enumColumn) allows(value string) bool {
+	for _, member := range c.members {
+		if strings.EqualFold(member, value) {
+			return true
+		}
+	}
+
+	return false
+}
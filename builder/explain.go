@@ -0,0 +1,10 @@
+package builder
+
+// Explain runs EXPLAIN for the built query and returns the plan rows (id,
+// select_type, table, type, possible_keys, key, rows, Extra, ...), with the
+// same parameters and connection the query itself would use.
+func (qb *QueryBuilder) Explain() ([]map[string]interface{}, error) {
+	query, params := qb.Build()
+
+	return qb.fetchRows("EXPLAIN "+query, params, qb.boundColumns())
+}
@@ -0,0 +1,32 @@
+package builder
+
+import "fmt"
+
+// SelectFields selects only the columns behind the requested API fields, to
+// avoid over-fetching in resolvers. columns maps field names to columns;
+// fields without a mapping (computed fields, __typename, ...) are skipped.
+// A column that differs from its field name is aliased to the field name.
+func (qb *QueryBuilder) SelectFields(fields []string, columns map[string]string) *QueryBuilder {
+	seen := make(map[string]bool, len(fields))
+	selected := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		column, ok := columns[field]
+		if !ok || seen[field] {
+			continue
+		}
+		seen[field] = true
+
+		if column != field {
+			column = fmt.Sprintf("%s AS %s", column, field)
+		}
+		selected = append(selected, column)
+	}
+
+	if len(selected) == 0 {
+		qb.setError(fmt.Errorf("none of the requested fields %v can be selected", fields))
+		return qb
+	}
+
+	return qb.Select(selected...)
+}
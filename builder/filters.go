@@ -0,0 +1,36 @@
+package builder
+
+// AddGlobalFilter applies fn to every builder on table before it is first
+// rendered, e.g. to hide unpublished rows:
+//
+//	d.AddGlobalFilter("posts", func(qb *QueryBuilder) { qb.Where("is_published", "=", 1) })
+//
+// The builder's own conditions are grouped first, so an OrWhere cannot
+// escape the filter. Builders opt out with WithoutGlobalFilters.
+func (d *DB) AddGlobalFilter(table string, fn func(qb *QueryBuilder)) *DB {
+	if d.globalFilters == nil {
+		d.globalFilters = make(map[string][]func(*QueryBuilder))
+	}
+	d.globalFilters[table] = append(d.globalFilters[table], fn)
+
+	return d
+}
+
+// WithoutGlobalFilters skips the table's global filters for this builder.
+func (qb *QueryBuilder) WithoutGlobalFilters() *QueryBuilder {
+	qb.filtersBypass = true
+
+	return qb
+}
+
+func (qb *QueryBuilder) applyGlobalFilters() {
+	filters := qb.db.globalFilters[qb.table]
+	if len(filters) == 0 || qb.filtersBypass {
+		return
+	}
+
+	qb.groupWhere()
+	for _, fn := range filters {
+		fn(qb)
+	}
+}
@@ -0,0 +1,111 @@
+package builder
+
+import "strings"
+
+// Fragment is a reusable set of select, join and where clauses with their
+// parameters, recorded once and applied to any number of builders:
+//
+//	activeAdults := builder.NewFragment(func(qb *builder.QueryBuilder) {
+//		qb.Where("status", "=", "active").Where("age", ">=", 18)
+//	})
+//	db.Table("users").Apply(activeAdults).OrderBy("name").Get()
+//
+// Unlike a ScopeFunc, a Fragment is plain data: its where conditions are
+// grouped into one, so they cannot be escaped by the target's OrWhere, and
+// applying it never touches a connection.
+type Fragment struct {
+	columns      []string
+	joins        []string
+	where        []string
+	params       []interface{}
+	paramColumns []string
+	selectParams map[string][]interface{}
+	err          error
+}
+
+// NewFragment records the clauses build adds to a blank builder. Any builder
+// method adding select columns, joins or where conditions can be used,
+// including Apply with other fragments; ordering, limits and execution are
+// ignored.
+func NewFragment(build func(qb *QueryBuilder)) *Fragment {
+	qb := &QueryBuilder{db: &DB{}, limit: -1, offset: -1}
+	build(qb)
+	qb.groupWhere()
+
+	return &Fragment{
+		columns:      qb.columns,
+		joins:        qb.joins,
+		where:        qb.where,
+		params:       qb.parameters,
+		paramColumns: qb.paramColumns,
+		selectParams: qb.selectParams,
+		err:          qb.err,
+	}
+}
+
+// Apply adds the clauses of the fragments to the builder, in order; their
+// parameters follow the builder's own.
+func (qb *QueryBuilder) Apply(fragments ...*Fragment) *QueryBuilder {
+	for _, f := range fragments {
+		if f.err != nil {
+			qb.setError(f.err)
+		}
+		qb.columns = append(qb.columns, f.columns...)
+		qb.joins = append(qb.joins, f.joins...)
+		qb.where = append(qb.where, f.where...)
+		qb.parameters = append(qb.parameters, f.params...)
+		qb.paramColumns = append(qb.paramColumns, f.paramColumns...)
+		for expr, params := range f.selectParams {
+			if qb.selectParams == nil {
+				qb.selectParams = make(map[string][]interface{})
+			}
+			qb.selectParams[expr] = params
+		}
+	}
+
+	return qb
+}
+
+// AnyOf combines the where conditions of the fragments with OR, so the
+// result matches rows matching any of them. Their select columns and joins
+// are kept.
+func AnyOf(fragments ...*Fragment) *Fragment {
+	combined := &Fragment{}
+	var conditions []string
+	for _, f := range fragments {
+		if f.err != nil && combined.err == nil {
+			combined.err = f.err
+		}
+		combined.columns = append(combined.columns, f.columns...)
+		combined.joins = append(combined.joins, f.joins...)
+		conditions = append(conditions, f.where...)
+		combined.params = append(combined.params, f.params...)
+		combined.paramColumns = append(combined.paramColumns, f.paramColumns...)
+		for expr, params := range f.selectParams {
+			if combined.selectParams == nil {
+				combined.selectParams = make(map[string][]interface{})
+			}
+			combined.selectParams[expr] = params
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+	case 1:
+		combined.where = conditions
+	default:
+		combined.where = []string{"(" + strings.Join(conditions, " OR ") + ")"}
+	}
+
+	return combined
+}
+
+// Not negates the where condition of a fragment.
+func Not(f *Fragment) *Fragment {
+	negated := *f
+	if len(f.where) > 0 {
+		negated.where = []string{"NOT (" + f.where[0] + ")"}
+	}
+
+	return &negated
+}
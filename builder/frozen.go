@@ -0,0 +1,70 @@
+package builder
+
+// Frozen is an immutable snapshot of a builder. It is safe for concurrent
+// use and cheap to specialize: each New returns an independent builder with
+// the snapshot's clauses, so a base query can be built once and refined per
+// request:
+//
+//	var activeUsers = db.Table("users").Where("active", "=", 1).Freeze()
+//
+//	func list(ctx context.Context, role string) ([]map[string]interface{}, error) {
+//		return activeUsers.New().WithContext(ctx).Where("role", "=", role).Get()
+//	}
+type Frozen struct {
+	qb *QueryBuilder
+}
+
+// Freeze returns a snapshot of the builder's current clauses. The builder
+// itself can still be changed without affecting the snapshot.
+func (qb *QueryBuilder) Freeze() *Frozen {
+	return &Frozen{qb: qb.clone()}
+}
+
+// New returns a builder with the snapshot's clauses.
+func (f *Frozen) New() *QueryBuilder {
+	return f.qb.clone()
+}
+
+// Build renders the snapshot's statement, without changing the snapshot.
+func (f *Frozen) Build() (string, []interface{}) {
+	return f.New().Build()
+}
+
+// clone copies the builder so neither copy's changes reach the other.
+// Per-run state (captured results, the truncation flag, an active session
+// pin) is not carried over.
+func (qb *QueryBuilder) clone() *QueryBuilder {
+	c := *qb
+	c.partitions = copyStrings(qb.partitions)
+	c.columns = copyStrings(qb.columns)
+	c.joins = copyStrings(qb.joins)
+	c.where = copyStrings(qb.where)
+	c.having = copyStrings(qb.having)
+	c.comments = copyStrings(qb.comments)
+	c.paramColumns = copyStrings(qb.paramColumns)
+	c.parameters = append([]interface{}(nil), qb.parameters...)
+	c.havingParams = append([]interface{}(nil), qb.havingParams...)
+	if qb.selectParams != nil {
+		c.selectParams = make(map[string][]interface{}, len(qb.selectParams))
+		for expr, params := range qb.selectParams {
+			c.selectParams[expr] = params
+		}
+	}
+	c.sessionVars = append([]sessionVar(nil), qb.sessionVars...)
+	c.contextScopes = append([]ScopeFunc(nil), qb.contextScopes...)
+	c.txOptions = append([]TxOption(nil), qb.txOptions...)
+	c.origins = append([]clauseOrigin(nil), qb.origins...)
+	if qb.rowLimit != nil {
+		limit := *qb.rowLimit
+		c.rowLimit = &limit
+	}
+	c.captured = nil
+	c.truncated = false
+	c.sessionActive = false
+
+	return &c
+}
+
+func copyStrings(values []string) []string {
+	return append([]string(nil), values...)
+}
@@ -0,0 +1,20 @@
+package builder
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// testDB returns a DB on a pool that is never connected to, for tests that
+// only build SQL.
+func testDB(t testing.TB) *DB {
+	t.Helper()
+
+	conn, err := sql.Open("mysql", "test@tcp(127.0.0.1:1)/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewDB(conn)
+}
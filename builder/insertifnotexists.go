@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// insertIfNotExistsRetries is how often InsertIfNotExists retries after losing
+// a deadlock to a concurrent call.
+const insertIfNotExistsRetries = 3
+
+// InsertIfNotExists inserts data unless a row matching the conditions added
+// by uniqueWhere exists, in one statement, and reports whether it inserted:
+//
+//	inserted, err := db.Table("subscriptions").InsertIfNotExists(
+//		map[string]interface{}{"user_id": 7, "list": "news"},
+//		func(q *builder.QueryBuilder) { q.Where("user_id", "=", 7).Where("list", "=", "news") },
+//	)
+//
+// It renders INSERT INTO t (...) SELECT ... FROM DUAL WHERE NOT EXISTS
+// (SELECT 1 FROM t WHERE ...), for tables where a unique index (and so
+// INSERT IGNORE or ON DUPLICATE KEY) is not an option. Index the condition
+// columns, or the whole table is locked.
+//
+// Under REPEATABLE READ (InnoDB's default) the subquery locks the range it
+// reads, gaps included, so concurrent calls do not both insert: one of them
+// fails with a deadlock, and outside a transaction it is retried (up to
+// three times) and then reports false. Inside a transaction the deadlock has
+// rolled the transaction back, so the error is returned for the caller to
+// retry the whole transaction. READ COMMITTED takes no gap locks, and there
+// concurrent calls can both insert.
+func (qb *QueryBuilder) InsertIfNotExists(data map[string]interface{}, uniqueWhere func(q *QueryBuilder)) (bool, error) {
+	if len(data) == 0 {
+		return false, fmt.Errorf("no data to insert")
+	}
+	if err := qb.assignKey(data); err != nil {
+		return false, err
+	}
+	data, err := qb.prepareWrite("insert", data)
+	if err != nil {
+		return false, err
+	}
+
+	exists := qb.db.Table(qb.table).WithContext(qb.context())
+	uniqueWhere(exists)
+	where := exists.whereClause()
+	if exists.err != nil {
+		return false, exists.err
+	}
+	if where == "" {
+		return false, fmt.Errorf("insert into %s: no uniqueness conditions given", qb.table)
+	}
+
+	columns := make([]string, 0, len(data))
+	params := make([]interface{}, 0, len(data)+len(exists.parameters))
+	for column, value := range data {
+		columns = append(columns, column)
+		params = append(params, value)
+	}
+	paramColumns := append(append([]string{}, columns...), exists.paramColumns...)
+	params = append(params, exists.parameters...)
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM %s%s)",
+		qb.table, strings.Join(columns, ","), placeholders(len(columns)), exists.from(), where)
+
+	result, err := qb.execWrite("insert", query, params, paramColumns, []map[string]interface{}{data})
+	_, standalone := qb.runner.(txBeginner)
+	for retry := 0; retry < insertIfNotExistsRetries && standalone && IsDeadlock(err); retry++ {
+		result, err = qb.execWrite("insert", query, params, paramColumns, []map[string]interface{}{data})
+	}
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+
+	return n > 0, err
+}
@@ -0,0 +1,72 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// deadlockRunner fails the first deadlocks statements with error 1213, then
+// reports zero rows affected, as when the concurrent call won.
+type deadlockRunner struct {
+	deadlocks int
+	execs     int
+}
+
+func (r *deadlockRunner) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (r *deadlockRunner) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	r.execs++
+	if r.execs <= r.deadlocks {
+		return nil, &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	}
+	return rowsAffected(0), nil
+}
+
+type beginningRunner struct{ *deadlockRunner }
+
+func (beginningRunner) BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error) {
+	return nil, errors.New("unexpected transaction")
+}
+
+type rowsAffected int64
+
+func (r rowsAffected) LastInsertId() (int64, error) { return 0, nil }
+func (r rowsAffected) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestInsertIfNotExistsRetriesDeadlocks(t *testing.T) {
+	insert := func(runner Runner) (bool, error) {
+		return testDB(t).Table("subscriptions").UseConnection(runner).InsertIfNotExists(
+			map[string]interface{}{"user_id": 7},
+			func(q *QueryBuilder) { q.Where("user_id", "=", 7) })
+	}
+
+	pool := &deadlockRunner{deadlocks: 1}
+	if inserted, err := insert(beginningRunner{pool}); err != nil || inserted {
+		t.Errorf("after a deadlock: got %v, %v; want false, nil", inserted, err)
+	}
+	if pool.execs != 2 {
+		t.Errorf("ran %d statements, want 2", pool.execs)
+	}
+
+	pool = &deadlockRunner{deadlocks: 10}
+	if _, err := insert(beginningRunner{pool}); !IsDeadlock(err) {
+		t.Errorf("persistent deadlock: got %v", err)
+	}
+	if pool.execs != 1+insertIfNotExistsRetries {
+		t.Errorf("ran %d statements, want %d", pool.execs, 1+insertIfNotExistsRetries)
+	}
+
+	tx := &deadlockRunner{deadlocks: 1}
+	if _, err := insert(tx); !IsDeadlock(err) {
+		t.Errorf("in a transaction: got %v, want the deadlock", err)
+	}
+	if tx.execs != 1 {
+		t.Errorf("retried inside a transaction: %d statements", tx.execs)
+	}
+}
@@ -0,0 +1,128 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sqlizer is anything that renders to SQL with "?" placeholders and their
+// arguments. Its method set matches squirrel.Sqlizer, so squirrel
+// expressions can be passed to WhereSql and a QueryBuilder can be used
+// wherever squirrel expects a Sqlizer, e.g. as a subquery.
+type Sqlizer interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// ToSql renders the SELECT statement and its parameters, reporting any
+// builder misuse. It makes QueryBuilder a squirrel.Sqlizer.
+func (qb *QueryBuilder) ToSql() (string, []interface{}, error) {
+	query, params := qb.Build()
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+	if err := qb.checkParamCount(query, params); err != nil {
+		return "", nil, err
+	}
+
+	return query, params, nil
+}
+
+// ToSqlizer returns the builder as a Sqlizer, for APIs that take the
+// interface rather than a concrete type.
+func (qb *QueryBuilder) ToSqlizer() Sqlizer {
+	return qb
+}
+
+type expr struct {
+	sql  string
+	args []interface{}
+}
+
+func (e expr) ToSql() (string, []interface{}, error) {
+	return expandIn(e.sql, e.args)
+}
+
+// Expr wraps a condition and its arguments as a Sqlizer for WhereSql. As
+// with sqlx.In, a slice argument expands its placeholder into one per
+// element; queries sqlx.In has already expanded pass through unchanged:
+//
+//	qb.WhereSql(builder.Expr("status IN (?) AND owner_id = ?", []string{"a", "b"}, 7))
+func Expr(sql string, args ...interface{}) Sqlizer {
+	return expr{sql: sql, args: args}
+}
+
+// WhereSql adds the condition rendered by s, in parentheses, ANDed with the
+// others: a squirrel expression, an Expr, or the output of sqlx.In.
+func (qb *QueryBuilder) WhereSql(s Sqlizer) *QueryBuilder {
+	condition, args, err := s.ToSql()
+	if err != nil {
+		qb.setError(fmt.Errorf("WhereSql: %w", err))
+		return qb
+	}
+	if n := countPlaceholders(condition); n != len(args) {
+		qb.setError(fmt.Errorf("%w: WhereSql condition %q has %d placeholders for %d arguments", ErrParamCount, condition, n, len(args)))
+		return qb
+	}
+
+	qb.where = append(qb.where, "("+condition+")")
+	qb.bind("", args...)
+
+	return qb
+}
+
+// expandIn replaces each placeholder whose argument is a slice (other than
+// []byte) with one placeholder per element, flattening the arguments.
+// Placeholders inside quoted strings and identifiers are left alone.
+func expandIn(query string, args []interface{}) (string, []interface{}, error) {
+	expand := false
+	for _, arg := range args {
+		if _, ok := toSlice(arg); ok {
+			expand = true
+			break
+		}
+	}
+	if !expand {
+		return query, args, nil
+	}
+
+	var b strings.Builder
+	var flat []interface{}
+	next := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				b.WriteByte(c)
+				i++
+				c = query[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			if next >= len(args) {
+				return "", nil, fmt.Errorf("%w: %q has more placeholders than %d arguments", ErrParamCount, query, len(args))
+			}
+			arg := args[next]
+			next++
+			if values, ok := toSlice(arg); ok {
+				if len(values) == 0 {
+					return "", nil, fmt.Errorf("empty slice for placeholder %d", next)
+				}
+				b.WriteString(placeholders(len(values)))
+				flat = append(flat, values...)
+				continue
+			}
+			flat = append(flat, arg)
+		}
+		b.WriteByte(c)
+	}
+	if next != len(args) {
+		return "", nil, fmt.Errorf("%w: %q has %d placeholders for %d arguments", ErrParamCount, query, next, len(args))
+	}
+
+	return b.String(), flat, nil
+}
@@ -0,0 +1,64 @@
+package builder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// quoteLiteral renders value as a MySQL literal.
+func quoteLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		return "'" + literalEscaper.Replace(v) + "'"
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return "'" + literalEscaper.Replace(fmt.Sprint(v)) + "'"
+	}
+}
+
+// interpolate replaces each ? placeholder outside quoted strings with the
+// literal form of the matching parameter.
+func interpolate(query string, params []interface{}) string {
+	var out strings.Builder
+	var quote byte
+	next := 0
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(query) {
+				out.WriteByte(c)
+				i++
+				c = query[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && next < len(params):
+			out.WriteString(quoteLiteral(params[next]))
+			next++
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
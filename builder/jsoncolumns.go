@@ -0,0 +1,170 @@
+package builder
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// JSONColumns marks columns of table as holding JSON documents: maps,
+// slices and structs bound to them by Insert and Update are encoded with
+// json.Marshal, and their values in Get results are decoded with
+// json.Unmarshal into maps, slices and scalars.
+func (d *DB) JSONColumns(table string, columns ...string) *DB {
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+
+	if d.jsonColumns == nil {
+		d.jsonColumns = make(map[string]map[string]bool)
+	}
+	if d.jsonColumns[table] == nil {
+		d.jsonColumns[table] = make(map[string]bool)
+	}
+	for _, column := range columns {
+		d.jsonColumns[table][column] = true
+	}
+
+	return d
+}
+
+// DetectJSONColumns treats the JSON columns of the given tables, as listed in
+// information_schema, as if registered with JSONColumns. They are loaded on
+// first use and cached. MariaDB reports its JSON columns as LONGTEXT, so
+// register those explicitly.
+func (d *DB) DetectJSONColumns(tables ...string) *DB {
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+
+	if d.jsonTables == nil {
+		d.jsonTables = make(map[string]bool)
+	}
+	for _, table := range tables {
+		d.jsonTables[table] = true
+	}
+
+	return d
+}
+
+// jsonColumnSet returns the JSON columns of the builder's table, registered
+// or detected.
+func (qb *QueryBuilder) jsonColumnSet() (map[string]bool, error) {
+	d := qb.db
+	d.schemaMu.Lock()
+	registered := d.jsonColumns[qb.table]
+	detect := d.jsonTables[qb.table]
+	cached, ok := d.jsonCache[qb.table]
+	d.schemaMu.Unlock()
+	if !detect {
+		return registered, nil
+	}
+	if ok {
+		return cached, nil
+	}
+
+	rows, err := qb.lookup().query("SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND DATA_TYPE = 'json'", []interface{}{qb.table})
+	if err != nil {
+		return nil, fmt.Errorf("error loading JSON columns of %s: %w", qb.table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool, len(registered))
+	for column := range registered {
+		columns[column] = true
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	d.schemaMu.Lock()
+	defer d.schemaMu.Unlock()
+	if d.jsonCache == nil {
+		d.jsonCache = make(map[string]map[string]bool)
+	}
+	d.jsonCache[qb.table] = columns
+
+	return columns, nil
+}
+
+// encodeJSONValues marshals the document values bound to JSON columns.
+// Strings and []byte are taken to be encoded already.
+func (qb *QueryBuilder) encodeJSONValues(data map[string]interface{}) error {
+	columns, err := qb.jsonColumnSet()
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	for column := range columns {
+		value, ok := data[column]
+		if !ok || !isJSONDocument(value) {
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("error encoding JSON column %s: %w", column, err)
+		}
+		data[column] = string(encoded)
+	}
+
+	return nil
+}
+
+func isJSONDocument(value interface{}) bool {
+	if isNull(value) {
+		return false
+	}
+	switch value.(type) {
+	case driver.Valuer, json.RawMessage, []byte, time.Time, *time.Time:
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return true
+	}
+
+	return false
+}
+
+// decodeJSONValues unmarshals the values of JSON columns, leaving columns
+// with a registered scanner to it.
+func (qb *QueryBuilder) decodeJSONValues(rows []map[string]interface{}, scanned map[string]bool) error {
+	columns, err := qb.jsonColumnSet()
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	for _, row := range rows {
+		for column := range columns {
+			if scanned[column] {
+				continue
+			}
+			// scanRows has already turned []byte values into strings
+			raw, ok := row[column].(string)
+			if !ok {
+				continue
+			}
+
+			var value interface{}
+			if err := json.Unmarshal([]byte(raw), &value); err != nil {
+				return fmt.Errorf("error decoding JSON column %s: %w", column, err)
+			}
+			row[column] = value
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package builder
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// KeyGenerator produces a new primary key value.
+type KeyGenerator func() (interface{}, error)
+
+// Key generators for SetPrimaryKey.
+var (
+	GenerateUUIDv4 KeyGenerator = func() (interface{}, error) { return NewUUIDv4() }
+	GenerateUUIDv7 KeyGenerator = func() (interface{}, error) { return NewUUIDv7() }
+	GenerateULID   KeyGenerator = func() (interface{}, error) { return NewULID() }
+)
+
+// BinaryUUID wraps a UUID generator so keys are stored packed in a BINARY(16)
+// column.
+func BinaryUUID(gen KeyGenerator) KeyGenerator {
+	return func() (interface{}, error) {
+		value, err := gen()
+		if err != nil {
+			return nil, err
+		}
+
+		return UUIDToBinary(fmt.Sprint(value))
+	}
+}
+
+type primaryKey struct {
+	column    string
+	generator KeyGenerator
+
+	// columns holds the columns of a composite key, in declaration order.
+	columns []string
+}
+
+// Key identifies a row by the values of its primary key columns, for tables
+// with a composite key:
+//
+//	db.Table("order_items").Find(builder.Key{"order_id": 7, "line": 2})
+type Key map[string]interface{}
+
+// SetPrimaryKey declares the primary key column of table, used by WhereKey.
+// When gen is not nil, Insert and BulkInsert fill the column with a generated
+// value for rows that do not set it; the value is written back into the
+// caller's map so it can be read after the insert.
+func (d *DB) SetPrimaryKey(table, column string, gen KeyGenerator) *DB {
+	if d.primaryKeys == nil {
+		d.primaryKeys = make(map[string]primaryKey)
+	}
+	d.primaryKeys[table] = primaryKey{column: column, generator: gen}
+
+	return d
+}
+
+// SetCompositeKey declares a primary key made of several columns, so Find,
+// FindOrFail, UpdateByKey and WhereKey accept the values in this order as
+// well as a Key.
+func (d *DB) SetCompositeKey(table string, columns ...string) *DB {
+	if d.primaryKeys == nil {
+		d.primaryKeys = make(map[string]primaryKey)
+	}
+	d.primaryKeys[table] = primaryKey{columns: columns}
+
+	return d
+}
+
+// errCompositeKey is returned by helpers that need a single-column key.
+var errCompositeKey = errors.New("builder: not supported for composite primary keys")
+
+// primaryKeyColumn returns the primary key column of the table, "id" unless
+// declared otherwise, or errCompositeKey for a composite key.
+func (qb *QueryBuilder) primaryKeyColumn() (string, error) {
+	if key, ok := qb.db.primaryKeys[qb.table]; ok {
+		if len(key.columns) > 0 {
+			return "", fmt.Errorf("%s: %w", qb.table, errCompositeKey)
+		}
+		return key.column, nil
+	}
+
+	return "id", nil
+}
+
+// WhereKey adds a condition on the table's primary key. id is a single value,
+// a Key, or the values of a key declared with SetCompositeKey as a
+// []interface{}. Pack textual UUIDs with UUIDToBinary first when the key is
+// stored as BINARY(16).
+func (qb *QueryBuilder) WhereKey(id interface{}) *QueryBuilder {
+	columns, values, err := qb.keyValues(id)
+	if err != nil {
+		qb.setError(err)
+		return qb
+	}
+	for i, column := range columns {
+		qb.Where(column, "=", values[i])
+	}
+
+	return qb
+}
+
+// keyValues resolves id into the key columns and their values.
+func (qb *QueryBuilder) keyValues(id interface{}) ([]string, []interface{}, error) {
+	var columns []string
+	if key, ok := qb.db.primaryKeys[qb.table]; ok && len(key.columns) > 0 {
+		columns = key.columns
+	}
+
+	switch id := id.(type) {
+	case Key:
+		return qb.keyFromMap(columns, id)
+	case map[string]interface{}:
+		return qb.keyFromMap(columns, id)
+	case []interface{}:
+		if columns == nil {
+			return nil, nil, fmt.Errorf("%s: key values given but no composite key declared with SetCompositeKey", qb.table)
+		}
+		if len(id) != len(columns) {
+			return nil, nil, fmt.Errorf("%s: key has %d columns, got %d values", qb.table, len(columns), len(id))
+		}
+		return columns, id, nil
+	}
+
+	if columns != nil {
+		return nil, nil, fmt.Errorf("%s: composite key (%s) needs a Key or %d values", qb.table, strings.Join(columns, ", "), len(columns))
+	}
+
+	column, err := qb.primaryKeyColumn()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []string{column}, []interface{}{id}, nil
+}
+
+// keyFromMap orders the values of key by the declared columns, or by column
+// name when the table has no declared composite key.
+func (qb *QueryBuilder) keyFromMap(columns []string, key map[string]interface{}) ([]string, []interface{}, error) {
+	if len(key) == 0 {
+		return nil, nil, fmt.Errorf("%s: empty key", qb.table)
+	}
+	if columns == nil {
+		for column := range key {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+	} else if len(key) != len(columns) {
+		return nil, nil, fmt.Errorf("%s: key has columns (%s), got %d", qb.table, strings.Join(columns, ", "), len(key))
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value, ok := key[column]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s: key column %s missing", qb.table, column)
+		}
+		if !utils.IsValidIdentifier(column) {
+			return nil, nil, fmt.Errorf("invalid key column: %q", column)
+		}
+		values[i] = value
+	}
+
+	return columns, values, nil
+}
+
+// Find fetches the row with the given primary key (see WhereKey), or nil
+// when there is none.
+func (qb *QueryBuilder) Find(id interface{}) (map[string]interface{}, error) {
+	row, err := qb.FindOrFail(id)
+	if errors.Is(err, ErrNoRows) {
+		return nil, nil
+	}
+
+	return row, err
+}
+
+// FindOrFail is Find returning ErrNoRows when the row does not exist.
+func (qb *QueryBuilder) FindOrFail(id interface{}) (map[string]interface{}, error) {
+	return qb.WhereKey(id).First()
+}
+
+// UpdateByKey updates the row with the given primary key (see WhereKey).
+func (qb *QueryBuilder) UpdateByKey(id interface{}, data map[string]interface{}) (sql.Result, error) {
+	return qb.WhereKey(id).Update(data)
+}
+
+// assignKey fills in a generated primary key when data lacks one.
+func (qb *QueryBuilder) assignKey(data map[string]interface{}) error {
+	key, ok := qb.db.primaryKeys[qb.table]
+	if !ok || key.generator == nil {
+		return nil
+	}
+	if value, exists := data[key.column]; exists && value != nil {
+		return nil
+	}
+
+	value, err := key.generator()
+	if err != nil {
+		return fmt.Errorf("error generating key for %s.%s: %w", qb.table, key.column, err)
+	}
+	data[key.column] = value
+
+	return nil
+}
+
+// NewUUIDv4 returns a random (version 4) UUID.
+func NewUUIDv4() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return formatUUID(u), nil
+}
+
+// NewUUIDv7 returns a time-ordered (version 7) UUID, which keeps B-tree
+// inserts sequential.
+func NewUUIDv7() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", err
+	}
+
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = (u[6] & 0x0f) | 0x70
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return formatUUID(u), nil
+}
+
+func formatUUID(u [16]byte) string {
+	h := hex.EncodeToString(u[:])
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// UUIDToBinary packs a textual UUID into 16 bytes for a BINARY(16) column.
+func UUIDToBinary(uuid string) ([]byte, error) {
+	raw, err := hex.DecodeString(strings.Replace(uuid, "-", "", -1))
+	if err != nil || len(raw) != 16 {
+		return nil, fmt.Errorf("invalid UUID: %s", uuid)
+	}
+
+	return raw, nil
+}
+
+// BinaryToUUID unpacks a BINARY(16) value into its textual form. It accepts
+// the string form Get returns for binary columns as well as []byte.
+func BinaryToUUID(value interface{}) (string, error) {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return "", fmt.Errorf("unsupported UUID value type: %T", value)
+	}
+	if len(raw) != 16 {
+		return "", fmt.Errorf("invalid binary UUID length: %d", len(raw))
+	}
+
+	var u [16]byte
+	copy(u[:], raw)
+
+	return formatUUID(u), nil
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID: a 48-bit millisecond timestamp followed by 80
+// random bits, encoded as 26 Crockford base32 characters.
+func NewULID() (string, error) {
+	var raw [16]byte
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	binary.BigEndian.PutUint16(raw[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(raw[2:6], uint32(ms))
+	if _, err := rand.Read(raw[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits encode into 26 characters of 5 bits, the first holding 3 bits
+	out := make([]byte, 26)
+	hi := binary.BigEndian.Uint64(raw[0:8])
+	lo := binary.BigEndian.Uint64(raw[8:16])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+
+	return string(out), nil
+}
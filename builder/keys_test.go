@@ -0,0 +1,25 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrimaryKeyColumnHasNoSideEffects(t *testing.T) {
+	d := testDB(t).SetCompositeKey("order_items", "order_id", "line")
+
+	qb := d.Table("order_items")
+	if _, err := qb.primaryKeyColumn(); !errors.Is(err, errCompositeKey) {
+		t.Fatalf("primaryKeyColumn error = %v, want errCompositeKey", err)
+	}
+	if _, _, err := qb.WhereKey(Key{"order_id": 7, "line": 2}).ToSql(); err != nil {
+		t.Fatalf("builder poisoned after reading the key: %v", err)
+	}
+
+	if _, _, err := d.Table("order_items").WhereKey(7).ToSql(); err == nil {
+		t.Fatal("scalar key on a composite key table: want error")
+	}
+	if column, err := d.Table("users").primaryKeyColumn(); err != nil || column != "id" {
+		t.Fatalf("default key = %q, %v", column, err)
+	}
+}
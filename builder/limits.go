@@ -0,0 +1,37 @@
+package builder
+
+type limitCaps struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+// DefaultLimit gives Get a LIMIT of n when the builder sets none, so list
+// endpoints cannot read a whole table by accident; n <= 0 disables it.
+func (d *DB) DefaultLimit(n int) *DB {
+	d.limitCaps.defaultLimit = n
+
+	return d
+}
+
+// MaxLimit clamps the LIMIT of Get to n, e.g. a per_page value taken from a
+// request; n <= 0 disables it. Unlike MaxRows an oversized limit is not an
+// error: the caller simply gets at most n rows.
+func (d *DB) MaxLimit(n int) *DB {
+	d.limitCaps.maxLimit = n
+
+	return d
+}
+
+// cappedLimit returns the builder's limit after DefaultLimit and MaxLimit.
+func (qb *QueryBuilder) cappedLimit() int {
+	limit := qb.limit
+	caps := qb.db.limitCaps
+	if limit < 0 && caps.defaultLimit > 0 {
+		limit = caps.defaultLimit
+	}
+	if caps.maxLimit > 0 && (limit < 0 || limit > caps.maxLimit) {
+		limit = caps.maxLimit
+	}
+
+	return limit
+}
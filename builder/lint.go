@@ -0,0 +1,134 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSQL is returned when a built statement fails the SQL lint.
+var ErrInvalidSQL = errors.New("builder: invalid SQL")
+
+// LintQueries checks every statement before it is sent (see LintSQL), to
+// catch builder misuse with a clear error instead of a server syntax error.
+// The check is a cheap token scan, but still work on every query; enable it
+// in development and tests.
+func (d *DB) LintQueries(enabled bool) *DB {
+	d.lint = enabled
+
+	return d
+}
+
+// LintSQL checks query, to be run with params parameters, for unbalanced
+// parentheses, a placeholder count that differs from params, and a dangling
+// AND/OR such as the "WHERE OR" left by an OrWhere used as the first
+// condition. It is a lightweight scan, not a parser: it only finds these
+// mistakes.
+func LintSQL(query string, params int) error {
+	tokens := sqlTokens(query)
+
+	depth, placeholders := 0, 0
+	for i, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth < 0 {
+				return fmt.Errorf("%w: unbalanced parentheses: unexpected ) in %q", ErrInvalidSQL, query)
+			}
+		case "?":
+			placeholders++
+		case "AND", "OR":
+			prev, next := "", ""
+			if i > 0 {
+				prev = tokens[i-1]
+			}
+			if i+1 < len(tokens) {
+				next = tokens[i+1]
+			}
+			if danglingBefore[prev] || danglingAfter[next] {
+				return fmt.Errorf("%w: dangling %s in %q", ErrInvalidSQL, token, query)
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("%w: unbalanced parentheses: %d unclosed ( in %q", ErrInvalidSQL, depth, query)
+	}
+	if placeholders != params {
+		return fmt.Errorf("%w: %d placeholders but %d parameters in %q", ErrInvalidSQL, placeholders, params, query)
+	}
+
+	return nil
+}
+
+// danglingBefore and danglingAfter are the tokens that cannot precede or
+// follow AND/OR; "" is the start or end of the statement.
+var (
+	danglingBefore = map[string]bool{
+		"": true, "WHERE": true, "HAVING": true, "ON": true, "(": true,
+		"AND": true, "OR": true, ",": true,
+	}
+	danglingAfter = map[string]bool{
+		"": true, ")": true, "GROUP": true, "ORDER": true, "LIMIT": true,
+		"HAVING": true, "UNION": true, "FOR": true, "AND": true, "OR": true,
+		",": true, ";": true,
+	}
+)
+
+// sqlTokens splits query into upper-cased words and symbols, skipping
+// comments. Quoted strings and identifiers become a single "'" or "`" token.
+func sqlTokens(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(query) && query[j] != c {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if c == '"' {
+				c = '\''
+			}
+			tokens = append(tokens, string(c))
+			i = j + 1
+		case isIdentChar(c):
+			j := i
+			for j < len(query) && (isIdentChar(query[j]) || query[j] == '.' || query[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, strings.ToUpper(query[i:j]))
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func (qb *QueryBuilder) lint(query string, params []interface{}) error {
+	if !qb.db.lint {
+		return nil
+	}
+
+	return LintSQL(query, len(params))
+}
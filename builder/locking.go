@@ -0,0 +1,47 @@
+package builder
+
+// ForUpdate locks the selected rows for writing until the transaction ends
+// (SELECT ... FOR UPDATE).
+func (qb *QueryBuilder) ForUpdate() *QueryBuilder {
+	qb.lock = "FOR UPDATE"
+
+	return qb
+}
+
+// ForShare locks the selected rows against writes by other transactions
+// until the transaction ends (SELECT ... FOR SHARE, MySQL 8.0+).
+func (qb *QueryBuilder) ForShare() *QueryBuilder {
+	qb.lock = "FOR SHARE"
+
+	return qb
+}
+
+// SkipLocked skips rows locked by other transactions instead of waiting for
+// them, so concurrent workers can each claim different rows. It implies
+// ForUpdate unless ForShare was chosen.
+func (qb *QueryBuilder) SkipLocked() *QueryBuilder {
+	qb.lockWait = "SKIP LOCKED"
+
+	return qb
+}
+
+// NoWait fails immediately when a selected row is locked by another
+// transaction. It implies ForUpdate unless ForShare was chosen.
+func (qb *QueryBuilder) NoWait() *QueryBuilder {
+	qb.lockWait = "NOWAIT"
+
+	return qb
+}
+
+// lockClause returns the locking clause ending the SELECT, or "".
+func (qb *QueryBuilder) lockClause() string {
+	lock := qb.lock
+	if lock == "" && qb.lockWait != "" {
+		lock = "FOR UPDATE"
+	}
+	if qb.lockWait != "" {
+		lock += " " + qb.lockWait
+	}
+
+	return lock
+}
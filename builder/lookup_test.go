@@ -0,0 +1,28 @@
+package builder
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLookupSharesNoState(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, 1)
+
+	var captured Result
+	qb := testDB(t).Table("users").WithContext(ctx).Capture(&captured).Comment("page=users").Where("id", "=", 1)
+	lookup := qb.lookup()
+
+	if lookup.captured != nil {
+		t.Error("lookup shares the caller's Capture result")
+	}
+	if got := lookup.withComment("SELECT 1"); got != "SELECT 1" {
+		t.Errorf("lookup carries the caller's comments: %q", got)
+	}
+	if len(lookup.where) != 0 || len(lookup.parameters) != 0 {
+		t.Error("lookup carries the caller's conditions")
+	}
+	if lookup.runner != qb.runner || lookup.context() != ctx || lookup.table != "users" {
+		t.Error("lookup does not run on the caller's connection, context and table")
+	}
+}
@@ -0,0 +1,90 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// MaintenanceResult is one row returned by ANALYZE, OPTIMIZE or CHECK TABLE.
+type MaintenanceResult struct {
+	Table   string // schema-qualified table name
+	Op      string // analyze, optimize or check
+	MsgType string // status, info, note, warning or error
+	MsgText string
+}
+
+// OK reports whether the row is a successful status row.
+func (r MaintenanceResult) OK() bool {
+	return r.MsgType == "status" && (r.MsgText == "OK" || r.MsgText == "Table is already up to date")
+}
+
+// AnalyzeTable refreshes the index statistics of tables.
+func (d *DB) AnalyzeTable(tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(context.Background(), "ANALYZE TABLE", true, tables)
+}
+
+// AnalyzeTableContext is AnalyzeTable with a context.
+func (d *DB) AnalyzeTableContext(ctx context.Context, tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(ctx, "ANALYZE TABLE", true, tables)
+}
+
+// OptimizeTable rebuilds tables to reclaim space and defragment indexes.
+// InnoDB reports this as a recreate followed by an analyze.
+func (d *DB) OptimizeTable(tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(context.Background(), "OPTIMIZE TABLE", true, tables)
+}
+
+// OptimizeTableContext is OptimizeTable with a context.
+func (d *DB) OptimizeTableContext(ctx context.Context, tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(ctx, "OPTIMIZE TABLE", true, tables)
+}
+
+// CheckTable checks tables for errors. It does not modify them and is allowed
+// on a read-only DB.
+func (d *DB) CheckTable(tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(context.Background(), "CHECK TABLE", false, tables)
+}
+
+// CheckTableContext is CheckTable with a context.
+func (d *DB) CheckTableContext(ctx context.Context, tables ...string) ([]MaintenanceResult, error) {
+	return d.maintain(ctx, "CHECK TABLE", false, tables)
+}
+
+// maintain runs a table maintenance statement. These statements return rows,
+// so write is what marks the ones rejected on a read-only DB.
+func (d *DB) maintain(ctx context.Context, statement string, write bool, tables []string) ([]MaintenanceResult, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%s: no tables given", strings.ToLower(statement))
+	}
+	if write && d.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		if !utils.IsValidIdentifier(table) {
+			return nil, fmt.Errorf("invalid table name: %q", table)
+		}
+		quoted[i] = quoteQualified(table)
+	}
+
+	rows, err := d.Table(tables[0]).WithContext(ctx).query(statement+" "+strings.Join(quoted, ", "), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []MaintenanceResult
+	for rows.Next() {
+		var r MaintenanceResult
+		if err := rows.Scan(&r.Table, &r.Op, &r.MsgType, &r.MsgText); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
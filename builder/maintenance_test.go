@@ -0,0 +1,19 @@
+package builder
+
+import "testing"
+
+func TestQuoteQualified(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"users", "`users`"},
+		{"app.users", "`app`.`users`"},
+		{"app.users.id", "`app`.`users`.`id`"},
+	}
+	for _, tt := range tests {
+		if got := quoteQualified(tt.name); got != tt.want {
+			t.Errorf("quoteQualified(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// ErrUnsupported is returned for features the server does not support.
+var ErrUnsupported = errors.New("builder: unsupported by the server")
+
+// MariaDB is the MySQL dialect plus the MariaDB extensions the builder knows
+// about: RETURNING on INSERT and DELETE (10.5+) and sequences (10.3+).
+var MariaDB Dialect = mariadbDialect{}
+
+type mariadbDialect struct {
+	mysqlDialect
+}
+
+func (mariadbDialect) Name() string { return "mariadb" }
+
+// DetectDialect asks the server for its version and selects the MariaDB or
+// MySQL dialect accordingly. Call it once after connecting.
+func (d *DB) DetectDialect(ctx context.Context) error {
+	version, err := d.ServerVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version.MariaDB {
+		d.SetDialect(MariaDB)
+	} else {
+		d.SetDialect(MySQL)
+	}
+
+	return nil
+}
+
+// IsMariaDB reports whether the DB uses the MariaDB dialect.
+func (d *DB) IsMariaDB() bool {
+	return d.Dialect().Name() == "mariadb"
+}
+
+// returning renders a RETURNING clause; no columns means all of them.
+func (qb *QueryBuilder) returning(columns []string) (string, error) {
+	if len(columns) == 0 {
+		return " RETURNING *", nil
+	}
+	for _, column := range columns {
+		if !utils.IsValidIdentifier(column) {
+			return "", fmt.Errorf("invalid column name: %q", column)
+		}
+	}
+
+	return " RETURNING " + strings.Join(columns, ", "), nil
+}
+
+// InsertReturning inserts data and returns the inserted row as stored,
+// including defaults and generated values, with INSERT ... RETURNING
+// (MariaDB 10.5+). No columns means all of them.
+func (qb *QueryBuilder) InsertReturning(data map[string]interface{}, columns ...string) (map[string]interface{}, error) {
+	if err := qb.db.require(qb.context(), FeatureReturning); err != nil {
+		return nil, err
+	}
+	clause, err := qb.returning(columns)
+	if err != nil {
+		return nil, err
+	}
+	if err := qb.assignKey(data); err != nil {
+		return nil, err
+	}
+	data, err = qb.prepareWrite("insert", data)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(data))
+	params := make([]interface{}, 0, len(data))
+	for column, value := range data {
+		names = append(names, column)
+		params = append(params, value)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s", qb.table, strings.Join(names, ","), placeholders(len(names)), clause)
+
+	rows, err := qb.fetchWrite("insert", query, params, names, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoRows
+	}
+
+	return rows[0], nil
+}
+
+// DeleteReturning deletes the matching rows and returns them, with
+// DELETE ... RETURNING (MariaDB 10.5+). No columns means all of them.
+func (qb *QueryBuilder) DeleteReturning(columns ...string) ([]map[string]interface{}, error) {
+	if err := qb.db.require(qb.context(), FeatureReturning); err != nil {
+		return nil, err
+	}
+	clause, err := qb.returning(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "DELETE FROM " + qb.from() + qb.whereClause() + clause
+
+	return qb.fetchWrite("delete", query, qb.parameters, qb.paramColumns, nil)
+}
+
+// fetchWrite runs a write statement that returns rows, with the read-only
+// check and audit trail of execWrite. inserted is the inserted row, if any.
+func (qb *QueryBuilder) fetchWrite(action, query string, params []interface{}, columns []string, inserted map[string]interface{}) ([]map[string]interface{}, error) {
+	if qb.db.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	var result []map[string]interface{}
+	run := func() error {
+		var before []map[string]interface{}
+		if qb.audited() && action == "delete" {
+			var err error
+			if before, err = qb.auditedRows(); err != nil {
+				return err
+			}
+		}
+
+		rows, err := qb.query(query, params)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		if result, err = qb.scan(rows); err != nil {
+			return err
+		}
+
+		if !qb.audited() {
+			return nil
+		}
+		if action == "insert" {
+			return qb.writeAudit(action, nil, inserted)
+		}
+		for _, old := range before {
+			if err := qb.writeAudit(action, old, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var err error
+	if qb.audited() {
+		err = qb.inTransaction(func() error { return qb.withSessionVars(run) })
+	} else {
+		err = qb.withSessionVars(run)
+	}
+
+	return result, err
+}
+
+// NextValue returns the next value of a MariaDB sequence (10.3+), created
+// with CREATE SEQUENCE.
+func (d *DB) NextValue(sequence string) (int64, error) {
+	qb := d.Table(sequence)
+	if err := d.require(context.Background(), FeatureSequences); err != nil {
+		return 0, err
+	}
+	if !utils.IsValidIdentifier(sequence) {
+		return 0, fmt.Errorf("invalid sequence name: %q", sequence)
+	}
+
+	var value int64
+	if err := qb.scanOne("SELECT NEXT VALUE FOR "+sequence, nil, &value); err != nil {
+		return 0, err
+	}
+
+	return value, nil
+}
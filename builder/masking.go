@@ -0,0 +1,97 @@
+package builder
+
+import "strings"
+
+// MaskedValue replaces masked values in logs and dumps.
+const MaskedValue = "***"
+
+// MaskColumns redacts the given columns (e.g. password, token, email) in
+// PrintQuery/ToSQL output, slow query reports and MaskRows.
+func (d *DB) MaskColumns(columns ...string) *DB {
+	for _, column := range columns {
+		d.MaskColumnWith(column, func(interface{}) interface{} {
+			return MaskedValue
+		})
+	}
+
+	return d
+}
+
+// MaskColumnWith redacts column using a custom mask, e.g. one that keeps the
+// domain of an email address.
+func (d *DB) MaskColumnWith(column string, mask func(value interface{}) interface{}) *DB {
+	if d.masks == nil {
+		d.masks = make(map[string]func(interface{}) interface{})
+	}
+	d.masks[column] = mask
+
+	return d
+}
+
+// maskFor returns the mask registered for column, ignoring any table qualifier.
+func (d *DB) maskFor(column string) func(interface{}) interface{} {
+	if mask, ok := d.masks[column]; ok {
+		return mask
+	}
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		return d.masks[column[i+1:]]
+	}
+
+	return nil
+}
+
+// maskParams returns a copy of params with masked columns redacted. columns
+// names the column of each parameter; when it is shorter than params it is
+// aligned to the end, where builder-bound WHERE parameters sit, and when it
+// is longer the extra trailing columns are ignored.
+func (d *DB) maskParams(params []interface{}, columns []string) []interface{} {
+	if len(d.masks) == 0 || len(columns) == 0 {
+		return params
+	}
+
+	offset := len(params) - len(columns)
+	if offset < 0 {
+		columns = columns[:len(params)]
+		offset = 0
+	}
+
+	masked := make([]interface{}, len(params))
+	copy(masked, params)
+	for i, column := range columns {
+		if mask := d.maskFor(column); mask != nil {
+			masked[offset+i] = mask(masked[offset+i])
+		}
+	}
+
+	return masked
+}
+
+// MaskRows returns a copy of rows with masked columns redacted, for result
+// dumps and exports.
+func (d *DB) MaskRows(rows []map[string]interface{}) []map[string]interface{} {
+	if len(d.masks) == 0 {
+		return rows
+	}
+
+	masked := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		copied := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			if mask := d.maskFor(column); mask != nil {
+				value = mask(value)
+			}
+			copied[column] = value
+		}
+		masked = append(masked, copied)
+	}
+
+	return masked
+}
+
+// ToSQL returns the built query with its parameters interpolated, for logging
+// and debugging. Masked columns are redacted; never execute the result.
+func (qb *QueryBuilder) ToSQL() string {
+	query, params := qb.Build()
+
+	return interpolate(query, qb.db.maskParams(params, qb.boundColumns()))
+}
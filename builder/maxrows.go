@@ -0,0 +1,84 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooManyRows is returned when a result set exceeds MaxRows in
+// AbortOnMaxRows mode.
+var ErrTooManyRows = errors.New("builder: result set exceeds the row limit")
+
+// MaxRowsMode selects what happens when a result set exceeds MaxRows.
+type MaxRowsMode int
+
+const (
+	// AbortOnMaxRows fails the query with ErrTooManyRows.
+	AbortOnMaxRows MaxRowsMode = iota
+	// TruncateOnMaxRows returns the first rows and sets Truncated.
+	TruncateOnMaxRows
+)
+
+type rowLimit struct {
+	max  int
+	mode MaxRowsMode
+}
+
+// MaxRows guards every builder of d against result sets larger than n rows;
+// n <= 0 disables the guard. A builder's own MaxRows takes precedence.
+func (d *DB) MaxRows(n int, mode MaxRowsMode) *DB {
+	d.rowLimit = rowLimit{max: n, mode: mode}
+
+	return d
+}
+
+// MaxRows guards Get against result sets larger than n rows. The query is
+// sent with LIMIT n+1 (unless it already has a lower limit) so an oversized
+// result is detected without transferring it.
+func (qb *QueryBuilder) MaxRows(n int, mode MaxRowsMode) *QueryBuilder {
+	qb.rowLimit = &rowLimit{max: n, mode: mode}
+
+	return qb
+}
+
+// Truncated reports whether the last Get dropped rows because of MaxRows.
+func (qb *QueryBuilder) Truncated() bool {
+	return qb.truncated
+}
+
+func (qb *QueryBuilder) effectiveRowLimit() rowLimit {
+	if qb.rowLimit != nil {
+		return *qb.rowLimit
+	}
+
+	return qb.db.rowLimit
+}
+
+// guardLimit returns the LIMIT to send for a guarded read, after the DB's
+// limit caps.
+func (qb *QueryBuilder) guardLimit() int {
+	limit := qb.cappedLimit()
+	max := qb.effectiveRowLimit().max
+	if max > 0 && (limit < 0 || limit > max) {
+		return max + 1
+	}
+
+	return limit
+}
+
+// checkRowLimit applies the row limit to a scanned result that had more rows
+// available when more is true.
+func (qb *QueryBuilder) checkRowLimit(result []map[string]interface{}, more bool) ([]map[string]interface{}, error) {
+	qb.truncated = false
+	if !more {
+		return result, nil
+	}
+
+	limit := qb.effectiveRowLimit()
+	if limit.mode == TruncateOnMaxRows {
+		qb.truncated = true
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: more than %d rows from %s", ErrTooManyRows, limit.max, qb.table)
+}
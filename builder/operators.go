@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// WhereOp is Where for operators that come from runtime input: an operator
+// outside the whitelist is reported when the query runs instead of exiting
+// the process.
+func (qb *QueryBuilder) WhereOp(column, operator string, value interface{}) *QueryBuilder {
+	if !utils.AllowedOperators[operator] {
+		qb.setError(fmt.Errorf("invalid operator: %s", operator))
+		return qb
+	}
+
+	return qb.Where(column, operator, value)
+}
+
+// WhereNullSafeEq adds "column <=> value", MySQL's NULL-safe equality: it
+// matches NULL against NULL, where = matches nothing.
+func (qb *QueryBuilder) WhereNullSafeEq(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, "<=>", value)
+}
+
+// Eq adds "column = value".
+func (qb *QueryBuilder) Eq(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, "=", value)
+}
+
+// Neq adds "column != value".
+func (qb *QueryBuilder) Neq(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, "!=", value)
+}
+
+// Gt adds "column > value".
+func (qb *QueryBuilder) Gt(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, ">", value)
+}
+
+// Gte adds "column >= value".
+func (qb *QueryBuilder) Gte(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, ">=", value)
+}
+
+// Lt adds "column < value".
+func (qb *QueryBuilder) Lt(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, "<", value)
+}
+
+// Lte adds "column <= value".
+func (qb *QueryBuilder) Lte(column string, value interface{}) *QueryBuilder {
+	return qb.Where(column, "<=", value)
+}
@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// DefaultTimestampColumn is the column Latest and Oldest sort by when none is
+// given.
+const DefaultTimestampColumn = "created_at"
+
+// Latest orders newest first by the given columns, created_at by default.
+func (qb *QueryBuilder) Latest(columns ...string) *QueryBuilder {
+	return qb.orderByTimestamps("DESC", columns)
+}
+
+// Oldest orders oldest first by the given columns, created_at by default.
+func (qb *QueryBuilder) Oldest(columns ...string) *QueryBuilder {
+	return qb.orderByTimestamps("ASC", columns)
+}
+
+func (qb *QueryBuilder) orderByTimestamps(direction string, columns []string) *QueryBuilder {
+	if len(columns) == 0 {
+		columns = []string{DefaultTimestampColumn}
+	}
+
+	sorts := make([]string, len(columns))
+	for i, column := range columns {
+		if !utils.IsValidIdentifier(column) {
+			qb.setError(fmt.Errorf("invalid column name: %q", column))
+			return qb
+		}
+		sorts[i] = column + " " + direction
+	}
+
+	return qb.OrderBy(strings.Join(sorts, ", "))
+}
+
+// SetDefaultOrder gives the table's SELECTs an ORDER BY when they do not set
+// one, so listings come back in a stable order without repeating OrderBy:
+//
+//	d.SetDefaultOrder("posts", "created_at DESC, id DESC")
+//
+// Grouped queries are left unordered, as the order columns may not be
+// grouped. Builders opt out with Unordered.
+func (d *DB) SetDefaultOrder(table, order string) *DB {
+	if d.defaultOrders == nil {
+		d.defaultOrders = make(map[string]string)
+	}
+	d.defaultOrders[table] = order
+
+	return d
+}
+
+// Unordered skips the table's default order for this builder.
+func (qb *QueryBuilder) Unordered() *QueryBuilder {
+	qb.orderBypass = true
+
+	return qb
+}
+
+func (qb *QueryBuilder) applyDefaultOrder() {
+	if qb.orderBy != "" || qb.groupBy != "" || qb.orderBypass {
+		return
+	}
+	if order, ok := qb.db.defaultOrders[qb.table]; ok {
+		qb.orderBy = order
+	}
+}
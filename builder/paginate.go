@@ -0,0 +1,51 @@
+package builder
+
+import "fmt"
+
+// Page is one page of results with the totals needed to navigate the rest.
+type Page struct {
+	Rows     []map[string]interface{}
+	Total    int // rows matching the query across all pages
+	Page     int // 1-based
+	PerPage  int
+	LastPage int // 1 when there are no rows
+}
+
+// HasNext reports whether a page follows this one.
+func (p *Page) HasNext() bool {
+	return p.Page < p.LastPage
+}
+
+// HasPrev reports whether a page precedes this one.
+func (p *Page) HasPrev() bool {
+	return p.Page > 1
+}
+
+// Paginate fetches page (1-based) of perPage rows along with the total row
+// count, using two queries. perPage is capped by MaxLimit.
+func (qb *QueryBuilder) Paginate(page, perPage int) (*Page, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("invalid page: %d", page)
+	}
+	if perPage < 1 {
+		return nil, fmt.Errorf("invalid page size: %d", perPage)
+	}
+	perPage = qb.Limit(perPage).cappedLimit()
+
+	total, err := qb.clone().Count()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := qb.Limit(perPage).Offset((page - 1) * perPage).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	last := (total + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+
+	return &Page{Rows: rows, Total: total, Page: page, PerPage: perPage, LastPage: last}, nil
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Parallel runs independent queries concurrently, each on its own pooled
+// connection, and returns their results by name. See ParallelContext.
+func (d *DB) Parallel(queries map[string]*QueryBuilder) (map[string]Result, error) {
+	return d.ParallelContext(context.Background(), queries)
+}
+
+// ParallelContext runs the queries concurrently under a context derived from
+// ctx; the first failure cancels the remaining queries and is returned.
+// Builders must not share a transaction, as a *sql.Tx is not safe for
+// concurrent use.
+func (d *DB) ParallelContext(ctx context.Context, queries map[string]*QueryBuilder) (map[string]Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	results := make(map[string]Result, len(queries))
+
+	for name, qb := range queries {
+		wg.Add(1)
+		go func(name string, qb *QueryBuilder) {
+			defer wg.Done()
+
+			var result Result
+			rows, err := qb.WithContext(ctx).Capture(&result).Get()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("query %s: %w", name, err)
+					cancel()
+				}
+				return
+			}
+			result.Rows = rows
+			results[name] = result
+		}(name, qb)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
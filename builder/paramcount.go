@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrParamCount is returned when a statement's placeholders and parameters do
+// not match, before it is sent to the driver.
+var ErrParamCount = errors.New("builder: placeholder count does not match parameters")
+
+// checkParamCount verifies that query has one ? placeholder per parameter.
+// On a mismatch the error lists the placeholders per clause next to the
+// parameters the builder bound, which usually points at the clause at fault
+// (e.g. a Having or WhereRaw call given the wrong number of values).
+func (qb *QueryBuilder) checkParamCount(query string, params []interface{}) error {
+	n := countPlaceholders(query)
+	if n == len(params) {
+		return nil
+	}
+
+	having, selected := len(qb.havingParams), len(qb.selectedParams())
+	bound := fmt.Sprintf("%d bound by WHERE/SET", len(params)-having-selected)
+	if selected > 0 {
+		bound = fmt.Sprintf("%d by SELECT, ", selected) + bound
+	}
+	if having > 0 {
+		bound += fmt.Sprintf(", %d by HAVING", having)
+	}
+
+	return fmt.Errorf("%w: %d placeholders (%s) but %d parameters (%s) in %q",
+		ErrParamCount, n, placeholdersByClause(query), len(params), bound, query)
+}
+
+// countPlaceholders counts the ? outside quotes and comments.
+func countPlaceholders(query string) int {
+	if strings.IndexByte(query, '?') < 0 {
+		return 0
+	}
+
+	n := 0
+	for _, token := range sqlTokens(query) {
+		if token == "?" {
+			n++
+		}
+	}
+
+	return n
+}
+
+// clauseKeywords start a clause for placeholdersByClause.
+var clauseKeywords = map[string]string{
+	"SELECT": "SELECT", "FROM": "FROM", "JOIN": "JOIN", "WHERE": "WHERE",
+	"GROUP": "GROUP BY", "HAVING": "HAVING", "ORDER": "ORDER BY",
+	"LIMIT": "LIMIT", "SET": "SET", "VALUES": "VALUES", "DUPLICATE": "ON DUPLICATE KEY UPDATE",
+	"RETURNING": "RETURNING",
+}
+
+// placeholdersByClause describes how many placeholders each top-level clause
+// holds, e.g. "WHERE: 2, HAVING: 1".
+func placeholdersByClause(query string) string {
+	var order []string
+	counts := make(map[string]int)
+	clause, depth := "", 0
+	for _, token := range sqlTokens(query) {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case "?":
+			if _, seen := counts[clause]; !seen {
+				order = append(order, clause)
+			}
+			counts[clause]++
+		default:
+			if name, ok := clauseKeywords[token]; ok && depth == 0 {
+				clause = name
+			}
+		}
+	}
+
+	parts := make([]string, len(order))
+	for i, name := range order {
+		if name == "" {
+			name = "statement"
+		}
+		parts[i] = fmt.Sprintf("%s: %d", name, counts[order[i]])
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
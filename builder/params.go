@@ -0,0 +1,161 @@
+package builder
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"time"
+)
+
+// ParamConverter converts a parameter before it is bound, returning false to
+// leave it to the next converter and the built-in rules.
+type ParamConverter func(value interface{}) (interface{}, bool)
+
+// AddParamConverter registers fn for the parameters of every statement, for
+// custom types the driver cannot bind. Converters run in registration order,
+// before the built-in rules.
+func (d *DB) AddParamConverter(fn ParamConverter) *DB {
+	d.paramConverters = append(d.paramConverters, fn)
+
+	return d
+}
+
+// BindTimesIn binds time.Time parameters as their wall clock time in loc,
+// unless the builder sets its own Timezone. The driver would convert a
+// time.Time back to the DSN's loc, so converted times are bound as strings
+// ("2006-01-02 15:04:05.999999", or the BindTimeFormat layout).
+func (d *DB) BindTimesIn(loc *time.Location) *DB {
+	d.bindLocation = loc
+
+	return d
+}
+
+// BindTimeFormat binds time.Time parameters as strings in layout, e.g.
+// "2006-01-02 15:04:05", after any time zone conversion.
+func (d *DB) BindTimeFormat(layout string) *DB {
+	d.bindTimeFormat = layout
+
+	return d
+}
+
+// normalizeParams returns params converted for binding: registered
+// converters first, then bools become 1/0, driver.Valuer types (such as enum
+// types) their value, named types of a basic kind (type Status string) that
+// kind, and times are converted to the builder's or DB's time zone and
+// format. params is copied only when something changes.
+func (qb *QueryBuilder) normalizeParams(params []interface{}) []interface{} {
+	var normalized []interface{}
+	for i, param := range params {
+		value := qb.normalizeParam(param)
+		if normalized == nil {
+			if sameParam(value, param) {
+				continue
+			}
+			normalized = append(make([]interface{}, 0, len(params)), params[:i]...)
+		}
+		normalized = append(normalized, value)
+	}
+	if normalized == nil {
+		return params
+	}
+
+	return normalized
+}
+
+func (qb *QueryBuilder) normalizeParam(param interface{}) interface{} {
+	for _, convert := range qb.db.paramConverters {
+		if value, ok := convert(param); ok {
+			return value
+		}
+	}
+
+	switch v := param.(type) {
+	case nil, string, []byte, int64, float64:
+		return param
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case time.Time:
+		return qb.bindTime(v)
+	case driver.Valuer:
+		if nilValuerPointer(v) {
+			return nil
+		}
+		value, err := v.Value()
+		if err != nil {
+			return param // the driver reports the error
+		}
+		if t, ok := value.(time.Time); ok {
+			return qb.bindTime(t)
+		}
+		return value
+	}
+
+	// named types of a basic kind bind as that kind
+	rv := reflect.ValueOf(param)
+	switch rv.Kind() {
+	case reflect.String:
+		if rv.Type() != reflect.TypeOf("") {
+			return rv.String()
+		}
+	case reflect.Bool:
+		if rv.Bool() {
+			return int64(1)
+		}
+		return int64(0)
+	}
+
+	return param
+}
+
+// bindTimeLayout formats times converted to a time zone when no
+// BindTimeFormat is set: MySQL's DATETIME format with microseconds.
+const bindTimeLayout = "2006-01-02 15:04:05.999999"
+
+// bindTime applies the time zone and format for binding. A converted time is
+// always bound as a string: the driver formats a time.Time in the DSN's loc,
+// which would undo the conversion.
+func (qb *QueryBuilder) bindTime(t time.Time) interface{} {
+	loc := qb.location
+	if loc == nil {
+		loc = qb.db.bindLocation
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	if qb.db.bindTimeFormat != "" {
+		return t.Format(qb.db.bindTimeFormat)
+	}
+	if loc != nil {
+		return t.Format(bindTimeLayout)
+	}
+
+	return t
+}
+
+// valuerType is the reflect.Type of driver.Valuer.
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// nilValuerPointer reports whether v is a nil pointer to a type whose Value
+// has a value receiver, such as (*sql.NullString)(nil). Calling Value on it
+// would panic; database/sql binds it as NULL.
+func nilValuerPointer(v driver.Valuer) bool {
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil() && rv.Type().Elem().Implements(valuerType)
+}
+
+// sameParam reports whether normalizing left param unchanged. Only cheap,
+// comparable cases are checked; anything else counts as changed.
+func sameParam(value, param interface{}) bool {
+	switch param.(type) {
+	case nil, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value == param
+	case time.Time:
+		tv, ok := value.(time.Time)
+		return ok && tv.Equal(param.(time.Time)) && tv.Location() == param.(time.Time).Location()
+	}
+
+	return false
+}
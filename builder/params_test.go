@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestBindTimeInLocation(t *testing.T) {
+	at := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	dhaka := time.FixedZone("Asia/Dhaka", 6*60*60)
+
+	db := testDB(t)
+	if got := db.Table("events").bindTime(at); got != at {
+		t.Errorf("without a zone: got %v, want the time unchanged", got)
+	}
+
+	db.BindTimesIn(dhaka)
+	if got, want := db.Table("events").bindTime(at), "2024-03-02 00:30:00"; got != want {
+		t.Errorf("BindTimesIn: got %v, want %q", got, want)
+	}
+	if got, want := db.Table("events").bindTime(at.Add(1500*time.Microsecond)), "2024-03-02 00:30:00.0015"; got != want {
+		t.Errorf("BindTimesIn with fraction: got %v, want %q", got, want)
+	}
+
+	qb := db.Table("events").Timezone("UTC")
+	if got, want := qb.bindTime(at), "2024-03-01 18:30:00"; got != want {
+		t.Errorf("Timezone: got %v, want %q", got, want)
+	}
+
+	db.BindTimeFormat("2006-01-02")
+	if got, want := db.Table("events").bindTime(at), "2024-03-02"; got != want {
+		t.Errorf("BindTimeFormat: got %v, want %q", got, want)
+	}
+}
+
+func TestNormalizeParams(t *testing.T) {
+	type status string
+	params := testDB(t).Table("users").normalizeParams([]interface{}{true, false, status("active"), "x", int64(3)})
+	want := []interface{}{int64(1), int64(0), "active", "x", int64(3)}
+	for i := range want {
+		if params[i] != want[i] {
+			t.Errorf("param %d: got %#v, want %#v", i, params[i], want[i])
+		}
+	}
+}
+
+type testEnum string
+
+func (e testEnum) Value() (driver.Value, error) { return string(e), nil }
+
+func TestNormalizeNilValuerPointers(t *testing.T) {
+	qb := testDB(t).Table("users")
+	for _, param := range []interface{}{(*sql.NullString)(nil), (*testEnum)(nil)} {
+		if got := qb.normalizeParam(param); got != nil {
+			t.Errorf("normalizeParam(%T nil) = %#v, want nil", param, got)
+		}
+	}
+
+	e := testEnum("active")
+	if got := qb.normalizeParam(&e); got != "active" {
+		t.Errorf("normalizeParam(&enum) = %#v, want \"active\"", got)
+	}
+}
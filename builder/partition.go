@@ -0,0 +1,185 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// RangePartition is one partition of a RANGE COLUMNS partitioned table.
+// LessThan is the exclusive upper bound; nil means MAXVALUE.
+type RangePartition struct {
+	Name     string
+	LessThan interface{}
+}
+
+// MonthlyPartitions returns one partition per month from the month of from up
+// to and including the month of to, named pYYYYMM and bounded by the first day
+// of the following month, followed by a pmax catch-all partition.
+func MonthlyPartitions(from, to time.Time) []RangePartition {
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var parts []RangePartition
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		parts = append(parts, RangePartition{
+			Name:     "p" + month.Format("200601"),
+			LessThan: month.AddDate(0, 1, 0).Format("2006-01-02"),
+		})
+	}
+
+	return append(parts, RangePartition{Name: "pmax"})
+}
+
+// PartitionInfo describes one partition, as reported by
+// information_schema.PARTITIONS.
+type PartitionInfo struct {
+	Name        string
+	Method      string
+	Expression  string
+	Description string
+	Rows        int64
+}
+
+func (s *Schema) setPartitioning(spec string, maintenance bool) *Schema {
+	if s.partition != "" {
+		s.setError(fmt.Errorf("alter table %s: only one partitioning change per statement", s.table))
+	}
+	s.partition = spec
+	s.maintenance = maintenance
+
+	return s
+}
+
+func (s *Schema) rangeDefinitions(parts []RangePartition) string {
+	if len(parts) == 0 {
+		s.setError(fmt.Errorf("partition: no partitions given"))
+	}
+
+	defs := make([]string, len(parts))
+	for i, part := range parts {
+		bound := "MAXVALUE"
+		if part.LessThan != nil {
+			bound = quoteLiteral(part.LessThan)
+		}
+		defs[i] = "PARTITION " + s.name("partition", part.Name) + " VALUES LESS THAN (" + bound + ")"
+	}
+
+	return strings.Join(defs, ", ")
+}
+
+// PartitionByRange partitions the table by RANGE COLUMNS on column, which
+// suits DATE and DATETIME columns:
+//
+//	db.Schema("events").PartitionByRange("created_on", builder.MonthlyPartitions(from, to)...).Exec()
+func (s *Schema) PartitionByRange(column string, parts ...RangePartition) *Schema {
+	return s.setPartitioning("PARTITION BY RANGE COLUMNS("+s.name("partition", column)+") ("+s.rangeDefinitions(parts)+")", false)
+}
+
+// PartitionByHash partitions the table into n partitions by HASH on an
+// integer column.
+func (s *Schema) PartitionByHash(column string, n int) *Schema {
+	if n < 1 {
+		s.setError(fmt.Errorf("partition: invalid partition count: %d", n))
+	}
+
+	return s.setPartitioning(fmt.Sprintf("PARTITION BY HASH(%s) PARTITIONS %d", s.name("partition", column), n), false)
+}
+
+// AddPartitions appends range partitions to a RANGE partitioned table. The
+// bounds must be above the current highest partition, so a table with a
+// MAXVALUE partition has to use ReorganizePartition instead.
+func (s *Schema) AddPartitions(parts ...RangePartition) *Schema {
+	return s.setPartitioning("ADD PARTITION ("+s.rangeDefinitions(parts)+")", true)
+}
+
+// ReorganizePartition splits or merges existing partitions into parts, e.g.
+// to carve the next month out of a MAXVALUE partition.
+func (s *Schema) ReorganizePartition(names []string, parts ...RangePartition) *Schema {
+	return s.setPartitioning("REORGANIZE PARTITION "+s.partitionNames(names)+" INTO ("+s.rangeDefinitions(parts)+")", true)
+}
+
+// DropPartition drops the named partitions and the rows they hold.
+func (s *Schema) DropPartition(names ...string) *Schema {
+	return s.setPartitioning("DROP PARTITION "+s.partitionNames(names), true)
+}
+
+// RemovePartitioning turns the table back into a non-partitioned table,
+// keeping its rows.
+func (s *Schema) RemovePartitioning() *Schema {
+	return s.setPartitioning("REMOVE PARTITIONING", false)
+}
+
+func (s *Schema) partitionNames(names []string) string {
+	if len(names) == 0 {
+		s.setError(fmt.Errorf("partition: no partitions given"))
+	}
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = s.name("partition", name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// Partitions lists the partitions of table in the current database, in
+// partition order. A non-partitioned table yields no partitions.
+func (d *DB) Partitions(table string) ([]PartitionInfo, error) {
+	return d.PartitionsContext(context.Background(), table)
+}
+
+// PartitionsContext is Partitions with a context.
+func (d *DB) PartitionsContext(ctx context.Context, table string) ([]PartitionInfo, error) {
+	query := "SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION, PARTITION_DESCRIPTION, TABLE_ROWS" +
+		" FROM information_schema.PARTITIONS" +
+		" WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL" +
+		" ORDER BY PARTITION_ORDINAL_POSITION"
+
+	rows, err := d.Table("information_schema.PARTITIONS").WithContext(ctx).query(query, []interface{}{table})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []PartitionInfo
+	for rows.Next() {
+		var info PartitionInfo
+		var method, expression, description sql.NullString
+		var count sql.NullInt64
+		if err := rows.Scan(&info.Name, &method, &expression, &description, &count); err != nil {
+			return nil, err
+		}
+		info.Method, info.Expression, info.Description, info.Rows = method.String, expression.String, description.String, count.Int64
+		partitions = append(partitions, info)
+	}
+
+	return partitions, rows.Err()
+}
+
+// Partition restricts the query to the named partitions with a
+// PARTITION (...) clause, for SELECT and DELETE.
+func (qb *QueryBuilder) Partition(names ...string) *QueryBuilder {
+	for _, name := range names {
+		if !utils.IsValidIdentifier(name) {
+			qb.setError(fmt.Errorf("invalid partition name: %q", name))
+			return qb
+		}
+	}
+	qb.partitions = append(qb.partitions, names...)
+
+	return qb
+}
+
+// from returns the table reference with its partition selection.
+func (qb *QueryBuilder) from() string {
+	if len(qb.partitions) == 0 {
+		return qb.table
+	}
+
+	return qb.table + " PARTITION (" + strings.Join(qb.partitions, ", ") + ")"
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pivot turns the distinct values of pivotColumn into columns using
+// conditional aggregation, grouped by rowColumn. aggregate is either a
+// function call such as "SUM(amount)" or a bare function such as "COUNT":
+//
+//	Pivot("region", "quarter", []string{"Q1", "Q2"}, "SUM(amount)")
+//	// SELECT region, SUM(CASE WHEN quarter = ? THEN amount END) AS `Q1`, ...
+//	// ... GROUP BY region
+//
+// The values are bound as parameters.
+func (qb *QueryBuilder) Pivot(rowColumn, pivotColumn string, values []string, aggregate string) *QueryBuilder {
+	function, expr := aggregate, "1"
+	if open := strings.Index(aggregate, "("); open > 0 && strings.HasSuffix(aggregate, ")") {
+		function = aggregate[:open]
+		expr = aggregate[open+1 : len(aggregate)-1]
+	}
+	function = strings.ToUpper(strings.TrimSpace(function))
+	if function == "" {
+		qb.setError(fmt.Errorf("Pivot on %s: missing aggregate function", pivotColumn))
+		return qb
+	}
+	if strings.TrimSpace(expr) == "*" {
+		expr = "1" // CASE ... THEN * is not valid SQL
+	}
+
+	qb.Select(rowColumn)
+	for _, value := range values {
+		qb.selectWithParams(fmt.Sprintf("%s(CASE WHEN %s = ? THEN %s END) AS %s",
+			function, pivotColumn, expr, qb.quoteIdentifier(value)), value)
+	}
+
+	return qb.GroupBy(rowColumn)
+}
+
+// quoteIdentifier wraps name in backticks, escaping embedded backticks.
+func quoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// quoteQualified quotes each part of a dotted name ("app.users") on its own,
+// so the result names a table in a database rather than one table called
+// "app.users".
+func quoteQualified(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdentifier(part)
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	qb := testDB(t).Table("sales").Where("year", "=", 2024).Pivot("region", "quarter", []string{"Q1", `x\' OR 1=1 -- `}, "SUM(amount)")
+	query, params, err := qb.ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "SELECT region, SUM(CASE WHEN quarter = ? THEN amount END) AS `Q1`, SUM(CASE WHEN quarter = ? THEN amount END) AS `x\\' OR 1=1 -- ` FROM sales WHERE year = ? GROUP BY region"
+	if query != want {
+		t.Errorf("got  %q\nwant %q", query, want)
+	}
+	if wantParams := []interface{}{"Q1", `x\' OR 1=1 -- `, 2024}; !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params %#v, want %#v", params, wantParams)
+	}
+	if columns := qb.boundColumns(); len(columns) != 3 || columns[2] != "year" {
+		t.Errorf("bound columns %q", columns)
+	}
+}
+
+func TestPivotCountStar(t *testing.T) {
+	query, _, err := testDB(t).Table("tickets").Pivot("team", "status", []string{"open"}, "COUNT(*)").ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT team, COUNT(CASE WHEN status = ? THEN 1 END) AS `open` FROM tickets GROUP BY team"; query != want {
+		t.Errorf("got %q, want %q", query, want)
+	}
+}
+
+func TestPivotParamsDroppedWithColumns(t *testing.T) {
+	qb := testDB(t).Table("sales").Pivot("region", "quarter", []string{"Q1"}, "COUNT")
+	qb.columns = []string{"COUNT(*)"}
+	if params := qb.boundParams(); len(params) != 0 {
+		t.Errorf("replaced columns kept their params: %#v", params)
+	}
+}
+
+func TestPivotInFragment(t *testing.T) {
+	f := NewFragment(func(qb *QueryBuilder) {
+		qb.Pivot("region", "quarter", []string{"Q1"}, "COUNT")
+	})
+	_, params, err := testDB(t).Table("sales").Apply(f).Where("year", "=", 2024).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"Q1", 2024}; !reflect.DeepEqual(params, want) {
+		t.Errorf("params %#v, want %#v", params, want)
+	}
+}
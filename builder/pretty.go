@@ -0,0 +1,194 @@
+package builder
+
+import "strings"
+
+// PrettySQL returns the built statement formatted with one clause per line,
+// for readable logs and debugging of large joins. See FormatSQL.
+func (qb *QueryBuilder) PrettySQL() string {
+	query, _ := qb.Build()
+
+	return FormatSQL(query)
+}
+
+// clauseStarts begin a new line at the level of their statement.
+var clauseStarts = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true,
+	"ORDER": true, "LIMIT": true, "UNION": true, "SET": true, "VALUES": true,
+	"FOR": true, "RETURNING": true, "LOCK": true,
+}
+
+// joinModifiers may precede JOIN.
+var joinModifiers = map[string]bool{
+	"LEFT": true, "RIGHT": true, "INNER": true, "CROSS": true, "FULL": true,
+	"NATURAL": true, "OUTER": true, "STRAIGHT_JOIN": true,
+}
+
+// FormatSQL lays query out with each clause on its own line, select columns,
+// joins and AND/OR conditions indented below it, and subqueries indented one
+// level further. Quoted text is left untouched.
+//
+//	SELECT
+//	  u.id,
+//	  u.name
+//	FROM users u
+//	  LEFT JOIN posts p ON p.user_id = u.id
+//	WHERE u.active = ?
+//	  AND p.id IS NULL
+//	ORDER BY u.name
+func FormatSQL(query string) string {
+	tokens := prettyTokens(query)
+
+	type frame struct {
+		query  bool // a statement or subquery, rather than plain parentheses
+		level  int
+		clause string
+	}
+	stack := []frame{{query: true}}
+	var out strings.Builder
+	newline := func(level int) {
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString(strings.Repeat("  ", level))
+	}
+	// space keeps the original spacing within a line
+	space := func(token prettyToken) {
+		if out.Len() == 0 || !token.spaced {
+			return
+		}
+		if last := out.String()[out.Len()-1]; last == '\n' || last == ' ' {
+			return
+		}
+		out.WriteByte(' ')
+	}
+
+	between := false
+	for i, t := range tokens {
+		token := t.text
+		top := &stack[len(stack)-1]
+		upper := strings.ToUpper(token)
+		next := ""
+		if i+1 < len(tokens) {
+			next = strings.ToUpper(tokens[i+1].text)
+		}
+		prev := ""
+		if i > 0 {
+			prev = strings.ToUpper(tokens[i-1].text)
+		}
+
+		switch {
+		case token == "(":
+			space(t)
+			out.WriteString("(")
+			if next == "SELECT" {
+				stack = append(stack, frame{query: true, level: top.level + 2})
+			} else {
+				stack = append(stack, frame{level: top.level})
+			}
+			continue
+		case token == ")":
+			if len(stack) > 1 {
+				if top.query {
+					newline(stack[len(stack)-2].level + 1)
+				}
+				stack = stack[:len(stack)-1]
+			}
+			out.WriteString(")")
+			continue
+		case token == "," && top.query && top.clause == "SELECT":
+			out.WriteString(",")
+			newline(top.level + 1)
+			continue
+		}
+
+		if top.query {
+			switch {
+			case clauseStarts[upper] && !(upper == "FOR" && top.clause == "") && !(upper == "SET" && prev == "CHARACTER"):
+				newline(top.level)
+				out.WriteString(token)
+				top.clause = upper
+				if upper == "SELECT" {
+					newline(top.level + 1)
+				}
+				continue
+			case joinModifiers[upper] && !joinModifiers[prev] && (next == "JOIN" || joinModifiers[next]),
+				upper == "JOIN" && !joinModifiers[prev]:
+				newline(top.level + 1)
+				out.WriteString(token)
+				top.clause = "JOIN"
+				continue
+			case upper == "BETWEEN":
+				between = true
+			case (upper == "AND" || upper == "OR") && top.clause != "SELECT":
+				if between && upper == "AND" {
+					between = false
+					break
+				}
+				newline(top.level + 1)
+				out.WriteString(token)
+				continue
+			}
+		}
+
+		space(t)
+		out.WriteString(token)
+	}
+
+	return out.String()
+}
+
+type prettyToken struct {
+	text   string
+	spaced bool // preceded by whitespace in the query
+}
+
+// prettyTokens splits query into words, quoted strings and symbols, keeping
+// their text and noting where whitespace separated them.
+func prettyTokens(query string) []prettyToken {
+	var tokens []prettyToken
+	spaced := false
+	add := func(text string) {
+		tokens = append(tokens, prettyToken{text: text, spaced: spaced})
+		spaced = false
+	}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			spaced = true
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(query) && query[j] != c {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(query) {
+				j = len(query) - 1
+			}
+			add(query[i : j+1])
+			i = j + 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i:], "*/")
+			if end < 0 {
+				end = len(query) - i - 2
+			}
+			add(query[i : i+end+2])
+			i += end + 2
+		case c == '(' || c == ')' || c == ',':
+			add(string(c))
+			i++
+		default:
+			j := i
+			for j < len(query) && !strings.ContainsRune(" \t\n\r'\"`(),", rune(query[j])) {
+				j++
+			}
+			add(query[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Change is the before and after value of one column.
+type Change struct {
+	Before interface{}
+	After  interface{}
+}
+
+// RowDiff is what an update would do to one row.
+type RowDiff struct {
+	Row     map[string]interface{} // the row as it is now
+	Changes map[string]Change      // only the columns whose value differs
+}
+
+// Changed reports whether the update would modify the row.
+func (d RowDiff) Changed() bool {
+	return len(d.Changes) > 0
+}
+
+// Columns returns the changed columns in sorted order.
+func (d RowDiff) Columns() []string {
+	columns := make([]string, 0, len(d.Changes))
+	for column := range d.Changes {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// PreviewUpdate reports what Update(data) would change, without executing
+// it, e.g. for a confirmation screen: it selects the matching rows and
+// compares each column of data, as it would be bound, with the stored value.
+// The rows are selected like any other SELECT, so RestrictColumns applies.
+// data goes through the same tenant and validation checks as Update, so a
+// preview fails where the update would.
+func (qb *QueryBuilder) PreviewUpdate(data map[string]interface{}) ([]RowDiff, error) {
+	if _, err := qb.prepareWrite("update", data); err != nil {
+		return nil, err
+	}
+
+	// built like a SELECT, so column restrictions and implicit conditions apply
+	qb.applyImplicit()
+	buf := getBuffer(qb.estimateSize())
+	defer putBuffer(buf)
+	qb.writeSelect(buf)
+
+	rows, err := qb.fetchRows(buf.String(), qb.boundParams(), qb.boundColumns())
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]RowDiff, len(rows))
+	for i, row := range rows {
+		diff := RowDiff{Row: row, Changes: make(map[string]Change)}
+		for column, after := range data {
+			before, ok := row[column]
+			if !ok {
+				return nil, fmt.Errorf("preview update of %s: unknown column %s", qb.table, column)
+			}
+			if !sameValue(before, qb.normalizeParam(after)) {
+				diff.Changes[column] = Change{Before: before, After: after}
+			}
+		}
+		diffs[i] = diff
+	}
+
+	return diffs, nil
+}
+
+// storedTimeLayouts are the text forms of DATETIME and DATE values.
+var storedTimeLayouts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// sameValue compares a stored value with a new, normalized one. The driver
+// returns most values as strings, so times and numbers are parsed from them
+// (times as written by the driver, in UTC) and anything else is compared in
+// text form.
+func sameValue(stored, value interface{}) bool {
+	if stored == nil || value == nil {
+		return stored == nil && value == nil
+	}
+	if b, ok := stored.([]byte); ok {
+		stored = string(b)
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		if t, ok := stored.(time.Time); ok {
+			return t.Equal(v)
+		}
+		for _, layout := range storedTimeLayouts {
+			if t, err := time.ParseInLocation(layout, fmt.Sprint(stored), time.UTC); err == nil {
+				return t.Equal(v)
+			}
+		}
+		return false
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		a, errA := strconv.ParseFloat(fmt.Sprint(stored), 64)
+		b, errB := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if errA == nil && errB == nil {
+			return a == b
+		}
+	}
+
+	return fmt.Sprint(stored) == fmt.Sprint(value)
+}
@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSameValue(t *testing.T) {
+	at := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	qb := testDB(t).Table("users")
+	tests := []struct {
+		stored, after interface{}
+		same          bool
+	}{
+		{"1", true, true},
+		{"0", true, false},
+		{"0", false, true},
+		{"2024-03-01 18:30:00", at, true},
+		{"2024-03-01 18:30:00.000000", at, true},
+		{"2024-03-01 18:31:00", at, false},
+		{at, at, true},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2.50", 2.5, true},
+		{"3", 3, true},
+		{"3", 4, false},
+		{"abc", "abc", true},
+		{[]byte("abc"), "abc", true},
+		{nil, nil, true},
+		{nil, "x", false},
+	}
+	for _, tt := range tests {
+		if got := sameValue(tt.stored, qb.normalizeParam(tt.after)); got != tt.same {
+			t.Errorf("sameValue(%#v, %#v) = %v, want %v", tt.stored, tt.after, got, tt.same)
+		}
+	}
+}
+
+func TestPreviewUpdateRespectsRestrictions(t *testing.T) {
+	d := testDB(t).RestrictColumns("users", []string{"id", "name"})
+	var captured Result
+	d.Table("users").Capture(&captured).Where("id", "=", 1).PreviewUpdate(map[string]interface{}{"name": "x"})
+	if want := "SELECT id, name FROM users WHERE id = ?"; captured.SQL != want {
+		t.Errorf("preview ran %q, want %q", captured.SQL, want)
+	}
+}
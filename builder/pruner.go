@@ -0,0 +1,133 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// PruneProgress reports a deleted batch to Pruner.OnProgress.
+type PruneProgress struct {
+	Table   string
+	Cutoff  time.Time // rows older than this are deleted
+	Batch   int64     // rows deleted by this batch
+	Deleted int64     // rows deleted from the table so far in this run
+}
+
+type pruneRule struct {
+	table     string
+	column    string
+	retention time.Duration
+}
+
+// Pruner deletes rows past their retention period, a batch at a time so no
+// statement holds locks for long:
+//
+//	pruner := builder.NewPruner(db).
+//		Retain("sessions", "last_seen_at", 30*24*time.Hour).
+//		Retain("audit_logs", "created_at", 365*24*time.Hour)
+//	go pruner.Run(ctx, time.Hour)
+//
+// Index the date columns, or every batch scans the table. Purges cover all
+// tenants and ignore global filters, so soft-deleted rows are purged too.
+// Purged rows are not written to the audit trail.
+type Pruner struct {
+	db    *DB
+	rules []pruneRule
+
+	// Batch is the number of rows per DELETE (default 1000).
+	Batch int
+
+	// Pause is the sleep between batches, to leave room for replication.
+	Pause time.Duration
+
+	// OnProgress, when set, is called after every batch.
+	OnProgress func(PruneProgress)
+
+	// OnError, when set, is told about failed runs; Run keeps going either
+	// way.
+	OnError func(err error)
+}
+
+// NewPruner returns a Pruner for d with no tables.
+func NewPruner(d *DB) *Pruner {
+	return &Pruner{db: d, Batch: 1000}
+}
+
+// Retain keeps the rows of table whose column is within retention of now;
+// older ones are deleted by Prune.
+func (p *Pruner) Retain(table, column string, retention time.Duration) *Pruner {
+	p.rules = append(p.rules, pruneRule{table: table, column: column, retention: retention})
+
+	return p
+}
+
+// Run prunes every interval until ctx is cancelled, and returns ctx.Err().
+func (p *Pruner) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if _, err := p.Prune(ctx); err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// Prune deletes the expired rows of every table and returns the number
+// deleted per table. It stops at the first error, returning the counts so
+// far.
+func (p *Pruner) Prune(ctx context.Context) (map[string]int64, error) {
+	deleted := make(map[string]int64, len(p.rules))
+	for _, rule := range p.rules {
+		n, err := p.prune(ctx, rule)
+		deleted[rule.table] += n
+		if err != nil {
+			return deleted, fmt.Errorf("prune %s: %w", rule.table, err)
+		}
+	}
+
+	return deleted, nil
+}
+
+func (p *Pruner) prune(ctx context.Context, rule pruneRule) (int64, error) {
+	if !utils.IsValidIdentifier(rule.column) {
+		return 0, fmt.Errorf("invalid column name: %q", rule.column)
+	}
+	batch := p.Batch
+	if batch <= 0 {
+		batch = 1000
+	}
+
+	cutoff := time.Now().Add(-rule.retention)
+	var deleted int64
+	for {
+		qb := p.db.Table(rule.table).WithoutTenant().WithoutGlobalFilters().WithContext(ctx).Where(rule.column, "<", cutoff)
+		query := "DELETE FROM " + qb.from() + qb.whereClause() + " ORDER BY " + rule.column + " LIMIT " + strconv.Itoa(batch)
+		result, err := qb.exec(query, qb.parameters, qb.paramColumns)
+		if err != nil {
+			return deleted, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+
+		if p.OnProgress != nil && n > 0 {
+			p.OnProgress(PruneProgress{Table: rule.table, Cutoff: cutoff, Batch: n, Deleted: deleted})
+		}
+		if n < int64(batch) {
+			return deleted, nil
+		}
+		if p.Pause > 0 {
+			if err := sleepContext(ctx, p.Pause); err != nil {
+				return deleted, err
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package builder
+
+import "errors"
+
+// ErrReadOnly is returned for write statements on a read-only DB.
+var ErrReadOnly = errors.New("builder: write rejected on read-only connection")
+
+// ReadOnly rejects every statement that does not return rows (Insert,
+// Update, Delete, DDL, ...) with ErrReadOnly before it reaches the server,
+// for reporting services wired to a replica.
+func (d *DB) ReadOnly() *DB {
+	d.readOnly = true
+
+	return d
+}
+
+// IsReadOnly reports whether writes are rejected.
+func (d *DB) IsReadOnly() bool {
+	return d.readOnly
+}
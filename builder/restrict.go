@@ -0,0 +1,272 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RestrictColumns limits the columns of table that builders may select to
+// columns, so sensitive ones (password hashes, tokens) cannot be fetched by
+// dynamically built queries. SELECT * on the table selects the allowed
+// columns instead, and a select list naming any other column of the table,
+// directly or within an expression, fails when the query runs.
+func (d *DB) RestrictColumns(table string, columns []string) *DB {
+	if d.restrictedColumns == nil {
+		d.restrictedColumns = make(map[string][]string)
+	}
+	d.restrictedColumns[table] = columns
+
+	return d
+}
+
+// sqlWords are the keywords that may appear in a select expression and are
+// not column names.
+var sqlWords = map[string]bool{
+	"AS": true, "DISTINCT": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "AND": true, "OR": true, "NOT": true,
+	"NULL": true, "IS": true, "IN": true, "LIKE": true, "BETWEEN": true,
+	"TRUE": true, "FALSE": true, "INTERVAL": true, "DIV": true, "MOD": true,
+	"SECOND": true, "MINUTE": true, "HOUR": true, "DAY": true, "WEEK": true,
+	"MONTH": true, "YEAR": true, "SEPARATOR": true, "ASC": true, "DESC": true,
+}
+
+// applyColumnRestrictions expands SELECT * and checks the select list
+// against RestrictColumns.
+func (qb *QueryBuilder) applyColumnRestrictions() {
+	if len(qb.db.restrictedColumns) == 0 {
+		return
+	}
+
+	tables := qb.queryTables()
+	if len(tables.restricted) == 0 {
+		return
+	}
+
+	if len(qb.columns) == 0 {
+		allowed, restricted := qb.db.restrictedColumns[tables.base]
+		if len(qb.joins) > 0 || !restricted {
+			qb.setError(fmt.Errorf("select on %s: columns of %s are restricted; list them explicitly when joining", qb.table, strings.Join(tables.restricted, ", ")))
+			return
+		}
+		qb.columns = append([]string{}, allowed...)
+		return
+	}
+
+	for _, expr := range qb.columns {
+		if strings.TrimSpace(expr) == "*" {
+			qb.setError(fmt.Errorf("select on %s: * is not allowed, columns of %s are restricted", qb.table, strings.Join(tables.restricted, ", ")))
+			return
+		}
+		if err := qb.checkSelectExpr(expr, tables); err != nil {
+			qb.setError(err)
+			return
+		}
+	}
+}
+
+// queryTables maps the names and aliases a query can qualify columns with
+// to the tables they stand for.
+type queryTables struct {
+	base       string
+	byName     map[string]string
+	restricted []string // restricted tables in the query
+	joined     bool
+}
+
+func (qb *QueryBuilder) queryTables() queryTables {
+	tables := queryTables{byName: make(map[string]string), joined: len(qb.joins) > 0}
+
+	add := func(ref string) string {
+		table, alias := parseTableRef(ref)
+		if table != "" {
+			tables.byName[table] = table
+			if _, ok := qb.db.restrictedColumns[table]; ok && !containsFold(tables.restricted, table) {
+				tables.restricted = append(tables.restricted, table)
+			}
+		}
+		if alias != "" {
+			tables.byName[alias] = table
+		}
+		return table
+	}
+
+	tables.base = add(qb.table)
+	for _, join := range qb.joins {
+		add(joinRef(join))
+	}
+
+	return tables
+}
+
+// joinRef returns the table reference of a join clause, e.g. "orders o" in
+// "LEFT JOIN orders o ON o.user_id = u.id".
+func joinRef(join string) string {
+	ref := join[strings.Index(join, "JOIN ")+len("JOIN "):]
+	if on := strings.Index(strings.ToUpper(ref), " ON "); on >= 0 {
+		ref = ref[:on]
+	} else if using := strings.Index(strings.ToUpper(ref), " USING"); using >= 0 {
+		ref = ref[:using]
+	}
+
+	return ref
+}
+
+// parseTableRef splits "schema.table [AS] alias" into the table name and the
+// alias. A derived table has no name, only its alias.
+func parseTableRef(ref string) (table, alias string) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "(") {
+		if end := strings.LastIndex(ref, ")"); end >= 0 {
+			fields := strings.Fields(ref[end+1:])
+			if len(fields) > 0 {
+				alias = strings.Trim(fields[len(fields)-1], "`")
+			}
+		}
+		return "", alias
+	}
+
+	fields := strings.Fields(ref)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	table = strings.Replace(fields[0], "`", "", -1)
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = table[i+1:]
+	}
+	if len(fields) >= 2 {
+		alias = strings.Trim(fields[len(fields)-1], "`")
+		if strings.EqualFold(alias, "AS") {
+			alias = ""
+		}
+	}
+
+	return table, alias
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSelectExpr checks the columns referenced by a select expression. A
+// bare word right after a complete term is an alias, as is the word after
+// AS.
+func (qb *QueryBuilder) checkSelectExpr(expr string, tables queryTables) error {
+	afterTerm, afterAS := false, false
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != c {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + 1
+			afterTerm = true
+		case isIdentStart(c) || c == '`':
+			j := i
+			for j < len(expr) && (isIdentChar(expr[j]) || expr[j] == '.' || expr[j] == '`' || (expr[j] == '*' && expr[j-1] == '.')) {
+				j++
+			}
+			word := strings.Replace(expr[i:j], "`", "", -1)
+			function := strings.HasPrefix(strings.TrimLeft(expr[j:], " \t\n"), "(")
+			i = j
+
+			switch {
+			case afterAS:
+				afterAS, afterTerm = false, true
+			case strings.EqualFold(word, "AS"):
+				afterAS = true
+			case sqlWords[strings.ToUpper(word)]:
+				afterTerm = false
+			case function:
+				afterTerm = false
+			case afterTerm:
+				// alias without AS
+			default:
+				if err := qb.checkColumnRef(word, tables); err != nil {
+					return err
+				}
+				afterTerm = true
+			}
+		case c >= '0' && c <= '9':
+			for i < len(expr) && (isIdentChar(expr[i]) || expr[i] == '.') {
+				i++
+			}
+			afterTerm = true
+		case c == ')':
+			i++
+			afterTerm = true
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		default:
+			i++
+			afterTerm = false
+		}
+	}
+
+	return nil
+}
+
+// checkColumnRef checks a possibly qualified column name. Qualifiers are
+// resolved through the table aliases; an unqualified name belongs to the
+// builder's table, or, once tables are joined, to any of them, so it must be
+// allowed in every restricted one.
+func (qb *QueryBuilder) checkColumnRef(ref string, tables queryTables) error {
+	i := strings.LastIndex(ref, ".")
+	if i < 0 {
+		if !tables.joined {
+			return qb.checkAllowed(tables.base, ref)
+		}
+		for _, table := range tables.restricted {
+			if err := qb.checkAllowed(table, ref); err != nil {
+				return fmt.Errorf("%w; qualify columns when joining restricted tables", err)
+			}
+		}
+		return nil
+	}
+
+	qualifier, column := ref[:i], ref[i+1:]
+	table := qualifier
+	if j := strings.LastIndex(qualifier, "."); j >= 0 {
+		table = qualifier[j+1:] // schema.table.column
+	} else if resolved, ok := tables.byName[qualifier]; ok {
+		table = resolved
+	}
+
+	return qb.checkAllowed(table, column)
+}
+
+// checkAllowed checks column against the restrictions of table.
+func (qb *QueryBuilder) checkAllowed(table, column string) error {
+	allowed, ok := qb.db.restrictedColumns[table]
+	if !ok {
+		return nil
+	}
+	if column == "*" {
+		return fmt.Errorf("select on %s: %s.* is not allowed, columns are restricted", qb.table, table)
+	}
+	for _, name := range allowed {
+		if strings.EqualFold(name, column) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("select on %s: column %s.%s is not allowed", qb.table, table, column)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
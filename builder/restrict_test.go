@@ -0,0 +1,56 @@
+package builder
+
+import "testing"
+
+func TestRestrictColumnsJoins(t *testing.T) {
+	d := testDB(t).RestrictColumns("users", []string{"id", "name"})
+
+	tests := []struct {
+		name    string
+		build   func() *QueryBuilder
+		wantErr bool
+	}{
+		{"allowed qualified", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users", "users.id = posts.user_id").Select("posts.title", "users.name")
+		}, false},
+		{"restricted qualified", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users", "users.id = posts.user_id").Select("users.password_hash")
+		}, true},
+		{"restricted through alias", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id").Select("u.password_hash")
+		}, true},
+		{"restricted through AS alias", func() *QueryBuilder {
+			return d.Table("posts").LeftJoin("users AS u", "u.id = posts.user_id").Select("UPPER(u.password_hash) AS h")
+		}, true},
+		{"allowed through alias", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id").Select("u.name")
+		}, false},
+		{"unqualified joined column", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id").Select("password_hash")
+		}, true},
+		{"unqualified column allowed everywhere", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id").Select("name")
+		}, false},
+		{"star with restricted join", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id").Select("*")
+		}, true},
+		{"implicit star with restricted join", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("users u", "u.id = posts.user_id")
+		}, true},
+		{"aliased base table", func() *QueryBuilder {
+			return d.Table("users u").Select("u.password_hash")
+		}, true},
+		{"unrestricted tables", func() *QueryBuilder {
+			return d.Table("posts").InnerJoin("tags t", "t.post_id = posts.id").Select("*")
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.build().ToSql()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Result holds the outcome of a query, as filled in by Capture and returned
+// by Parallel.
+type Result struct {
+	Rows []map[string]interface{} // set by Parallel only
+
+	// SQL and Params are the last statement run, with sensitive parameters
+	// masked. Duration runs until the last row was scanned.
+	SQL      string
+	Params   []interface{}
+	Duration time.Duration
+
+	RowsReturned int
+	RowsAffected int64
+	LastInsertID int64
+}
+
+// Capture records the statement the builder runs next (Get, First, Insert,
+// Update, ...) into result, with its duration and row counts, so callers can
+// log or report it without wrapping each call:
+//
+//	var res builder.Result
+//	rows, err := db.Table("users").Capture(&res).Where("active", "=", 1).Get()
+//	log.Printf("%s took %s, %d rows", res.SQL, res.Duration, res.RowsReturned)
+//
+// When a call runs several statements, result describes the last one.
+func (qb *QueryBuilder) Capture(result *Result) *QueryBuilder {
+	qb.captured = result
+
+	return qb
+}
+
+func (qb *QueryBuilder) capture(query string, params []interface{}, columns []string, start time.Time) {
+	if qb.captured == nil {
+		return
+	}
+
+	*qb.captured = Result{SQL: query, Params: qb.db.maskParams(params, columns), Duration: time.Since(start)}
+	qb.captureStart = start
+}
+
+func (qb *QueryBuilder) captureExec(result sql.Result) {
+	if qb.captured == nil {
+		return
+	}
+
+	qb.captured.Duration = time.Since(qb.captureStart)
+	qb.captured.RowsAffected, _ = result.RowsAffected()
+	qb.captured.LastInsertID, _ = result.LastInsertId()
+}
+
+func (qb *QueryBuilder) captureRows(n int) {
+	if qb.captured == nil {
+		return
+	}
+
+	qb.captured.Duration = time.Since(qb.captureStart)
+	qb.captured.RowsReturned = n
+}
@@ -0,0 +1,240 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Runner is the part of database/sql that query execution needs. *sql.DB,
+// *sql.Tx and *sql.Conn all satisfy it, as can test fakes.
+type Runner interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// UseConnection runs this query on conn instead of the DB's default pool.
+// conn is either a Runner (*sql.DB, *sql.Tx, *sql.Conn) or the name of a
+// connection registered with DB.AddConnection.
+func (qb *QueryBuilder) UseConnection(conn interface{}) *QueryBuilder {
+	switch c := conn.(type) {
+	case Runner:
+		qb.runner = c
+	case string:
+		runner, ok := qb.db.connections[c]
+		if !ok {
+			qb.setError(fmt.Errorf("unknown connection: %s", c))
+			break
+		}
+		qb.runner = runner
+	default:
+		qb.setError(fmt.Errorf("unsupported connection type: %T", conn))
+	}
+
+	return qb
+}
+
+// setError records builder misuse; only the first error is kept.
+func (qb *QueryBuilder) setError(err error) {
+	if qb.err == nil {
+		if qb.debugging() {
+			err = fmt.Errorf("%w (at %s)", err, callerOutsidePackage())
+		}
+		qb.err = err
+	}
+}
+
+// WithContext sets the context used when the query is executed.
+func (qb *QueryBuilder) WithContext(ctx context.Context) *QueryBuilder {
+	qb.ctx = ctx
+
+	return qb
+}
+
+func (qb *QueryBuilder) context() context.Context {
+	if qb.ctx == nil {
+		return context.Background()
+	}
+
+	return qb.ctx
+}
+
+// lookup returns a builder for metadata queries made on qb's behalf. It runs
+// on qb's connection and context but shares none of its state, so the lookup
+// does not replace qb's captured statement or carry its comments.
+func (qb *QueryBuilder) lookup() *QueryBuilder {
+	return &QueryBuilder{
+		db:     qb.db,
+		runner: qb.runner,
+		ctx:    qb.ctx,
+		table:  qb.table,
+		limit:  -1,
+		offset: -1,
+	}
+}
+
+// query runs a statement that returns rows, binding the builder's params.
+func (qb *QueryBuilder) query(query string, params []interface{}) (*sql.Rows, error) {
+	return qb.queryColumns(query, params, qb.boundColumns())
+}
+
+// queryColumns is query for params other than the builder's own; columns
+// names the column each parameter is bound against, for masking.
+func (qb *QueryBuilder) queryColumns(query string, params []interface{}, columns []string) (*sql.Rows, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if err := qb.requireFeatures(); err != nil {
+		return nil, err
+	}
+	if err := qb.checkParamCount(query, params); err != nil {
+		return nil, qb.debugError(err)
+	}
+	if err := qb.lint(query, params); err != nil {
+		return nil, qb.debugError(err)
+	}
+
+	query = Rebind(qb.db.Dialect(), qb.withComment(query))
+	params = qb.normalizeParams(params)
+	start := time.Now()
+	defer qb.observe(query, params, columns, start)
+	qb.capture(query, params, columns, start)
+
+	rows, err := qb.runner.QueryContext(qb.context(), query, params...)
+
+	return rows, qb.diagnose(err)
+}
+
+// exec runs a statement that returns no rows. columns names the column each
+// parameter is bound against, for masking.
+func (qb *QueryBuilder) exec(query string, params []interface{}, columns []string) (sql.Result, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if qb.db.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	if len(qb.effectiveSessionVars()) > 0 && !qb.sessionActive {
+		var result sql.Result
+		err := qb.withSessionVars(func() error {
+			var err error
+			result, err = qb.exec(query, params, columns)
+			return err
+		})
+		return result, err
+	}
+	if err := qb.checkParamCount(query, params); err != nil {
+		return nil, qb.debugError(err)
+	}
+	if err := qb.lint(query, params); err != nil {
+		return nil, qb.debugError(err)
+	}
+
+	query = Rebind(qb.db.Dialect(), qb.withComment(query))
+	params = qb.normalizeParams(params)
+	start := time.Now()
+	defer qb.observe(query, params, columns, start)
+	qb.capture(query, params, columns, start)
+
+	result, err := qb.runner.ExecContext(qb.context(), query, params...)
+	if err == nil {
+		qb.captureExec(result)
+	}
+
+	return result, qb.diagnose(err)
+}
+
+// fetch runs a read binding the builder's params and scans every row.
+func (qb *QueryBuilder) fetch(query string, params []interface{}) ([]map[string]interface{}, error) {
+	return qb.fetchColumns(query, params, qb.boundColumns())
+}
+
+// fetchColumns is fetch for params other than the builder's own, bound
+// against columns as for queryColumns.
+func (qb *QueryBuilder) fetchColumns(query string, params []interface{}, columns []string) ([]map[string]interface{}, error) {
+	if qb.db.singleflight.enabled {
+		if pool, ok := qb.runner.(*sql.DB); ok && qb.err == nil {
+			return qb.fetchShared(pool, query, params, columns)
+		}
+	}
+
+	return qb.fetchRows(query, params, columns)
+}
+
+// fetchShared runs fetchRows once for concurrent callers of the same read.
+func (qb *QueryBuilder) fetchShared(pool *sql.DB, query string, params []interface{}, columns []string) ([]map[string]interface{}, error) {
+	key := qb.flightKey(pool, query, params)
+	shared := qb.clone()
+	shared.ctx = detachedContext{qb.context()}
+	call, err := qb.db.singleflight.do(qb.context(), key, func(call *flightCall) {
+		shared.captured = &call.captured
+		call.rows, call.err = shared.fetchRows(query, params, columns)
+		call.truncated = shared.truncated
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	qb.truncated = call.truncated
+	if qb.captured != nil {
+		*qb.captured = call.captured
+	}
+
+	return copyRows(call.rows), call.err
+}
+
+// flightKey identifies a read for singleflight: everything that shapes its
+// result. Context comments such as trace IDs are left out, so callers from
+// different requests still share the query.
+func (qb *QueryBuilder) flightKey(pool *sql.DB, query string, params []interface{}) string {
+	return fmt.Sprintf("%p\x00%s\x00%s\x00%#v\x00%#v\x00%#v", pool, strings.Join(qb.comments, ","), query,
+		qb.normalizeParams(params), qb.effectiveSessionVars(), qb.effectiveRowLimit())
+}
+
+func (qb *QueryBuilder) fetchRows(query string, params []interface{}, columns []string) (result []map[string]interface{}, err error) {
+	err = qb.withSessionVars(func() error {
+		rows, err := qb.queryColumns(query, params, columns)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		result, err = qb.scan(rows)
+		qb.captureRows(len(result))
+
+		return err
+	})
+
+	return result, err
+}
+
+// scanOne runs query and scans the first row into dest, like QueryRow.
+func (qb *QueryBuilder) scanOne(query string, params []interface{}, dest ...interface{}) error {
+	return qb.withSessionVars(func() error {
+		return qb.scanFirst(query, params, dest...)
+	})
+}
+
+func (qb *QueryBuilder) scanFirst(query string, params []interface{}, dest ...interface{}) error {
+	rows, err := qb.query(query, params)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SampleMethod trades the accuracy of Sample against its cost.
+type SampleMethod int
+
+const (
+	// SampleFiltered keeps each row with probability p, chosen so about
+	// twice n rows pass, and shuffles only those: one scan and no large
+	// sort. p comes from the table statistics when the builder has no
+	// conditions and from a COUNT(*) otherwise; if fewer than n rows pass
+	// (unlikely, or a stale estimate) the query is rerun with SampleExact.
+	SampleFiltered SampleMethod = iota
+
+	// SampleExact is ORDER BY RAND() LIMIT n: uniformly random, but MySQL
+	// sorts every matching row, which is slow on big tables.
+	SampleExact
+)
+
+// Sample returns up to n rows chosen at random among the matching ones,
+// with SampleFiltered.
+func (qb *QueryBuilder) Sample(n int) ([]map[string]interface{}, error) {
+	return qb.SampleWith(n, SampleFiltered)
+}
+
+// SampleWith is Sample with the given method.
+func (qb *QueryBuilder) SampleWith(n int, method SampleMethod) ([]map[string]interface{}, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("sample %s: invalid sample size: %d", qb.table, n)
+	}
+
+	core := qb.BuildSelectQuery()
+	params := qb.boundParams()
+	limit := " ORDER BY RAND() LIMIT " + strconv.Itoa(n)
+
+	if method == SampleFiltered {
+		total, err := qb.sampleTotal()
+		if err != nil {
+			return nil, err
+		}
+		// aim for 2n+10 rows, so falling short of n is very unlikely
+		if p := float64(2*n+10) / float64(total); total > 0 && p < 1 {
+			query := "SELECT * FROM (" + core + ") AS sample WHERE RAND() <= ?" + limit
+			// the columns cover p too, so masks stay aligned to the params
+			sampled := append(append([]interface{}{}, params...), p)
+			rows, err := qb.fetchColumns(query, sampled, append(append([]string{}, qb.boundColumns()...), ""))
+			if err != nil || len(rows) == n {
+				return rows, err
+			}
+		}
+	}
+
+	return qb.fetch("SELECT * FROM ("+core+") AS sample"+limit, params)
+}
+
+// sampleTotal estimates the number of matching rows, from the table
+// statistics when nothing narrows the table down.
+func (qb *QueryBuilder) sampleTotal() (int64, error) {
+	if len(qb.where) == 0 && len(qb.joins) == 0 && qb.groupBy == "" && len(qb.partitions) == 0 {
+		stats, err := qb.db.TableStatsContext(qb.context(), qb.table)
+		if err != nil {
+			return 0, err
+		}
+		if len(stats) == 1 && stats[0].Rows > 0 {
+			return stats[0].Rows, nil
+		}
+	}
+
+	count, err := qb.Count()
+
+	return int64(count), err
+}
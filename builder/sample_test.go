@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampleParamsStayMasked(t *testing.T) {
+	d := testDB(t).MaskColumns("email")
+
+	var captured Result
+	qb := d.Table("users").Capture(&captured).Where("email", "=", "jane@example.com")
+	core := qb.BuildSelectQuery()
+	params := qb.boundParams()
+	qb.fetchColumns("SELECT * FROM ("+core+") AS sample WHERE RAND() <= ?", append(append([]interface{}{}, params...), 0.25),
+		append(append([]string{}, qb.boundColumns()...), ""))
+
+	if want := []interface{}{MaskedValue, 0.25}; !reflect.DeepEqual(captured.Params, want) {
+		t.Errorf("captured params %#v, want %#v", captured.Params, want)
+	}
+	if !reflect.DeepEqual(qb.parameters, []interface{}{"jane@example.com"}) {
+		t.Errorf("builder params changed: %#v", qb.parameters)
+	}
+}
@@ -0,0 +1,244 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// ErrUnknownQuery is returned by Run for a name that was never registered.
+var ErrUnknownQuery = errors.New("builder: unknown saved query")
+
+// QueryFactory builds a saved query from its checked arguments.
+type QueryFactory func(d *DB, args Args) *QueryBuilder
+
+// QueryParam declares a parameter of a saved query. Its type is the type of
+// the example or default value given to RequiredParam or OptionalParam.
+type QueryParam struct {
+	Name     string
+	Type     reflect.Type
+	Default  interface{}
+	Required bool
+}
+
+// RequiredParam declares a parameter Run must be given, of the type of
+// example:
+//
+//	builder.RequiredParam("plan", "")
+func RequiredParam(name string, example interface{}) QueryParam {
+	return QueryParam{Name: name, Type: reflect.TypeOf(example), Required: true}
+}
+
+// OptionalParam declares a parameter that takes def when Run is not given
+// it.
+func OptionalParam(name string, def interface{}) QueryParam {
+	return QueryParam{Name: name, Type: reflect.TypeOf(def), Default: def}
+}
+
+type savedQuery struct {
+	factory QueryFactory
+	params  []QueryParam
+}
+
+// RegisterQuery saves a query under name, so it is defined once and run
+// anywhere with Run:
+//
+//	db.RegisterQuery("activeUsersByPlan", func(d *builder.DB, args builder.Args) *builder.QueryBuilder {
+//		return d.Table("users").Where("status", "=", "active").Where("plan", "=", args.String("plan"))
+//	}, builder.RequiredParam("plan", ""), builder.OptionalParam("limit", 100))
+//
+// Run checks the arguments against params before calling factory, so
+// factory can read them with the typed Args accessors.
+func (d *DB) RegisterQuery(name string, factory QueryFactory, params ...QueryParam) *DB {
+	if d.savedQueries == nil {
+		d.savedQueries = make(map[string]savedQuery)
+	}
+	d.savedQueries[name] = savedQuery{factory: factory, params: params}
+
+	return d
+}
+
+// Run runs the saved query name with args and returns its rows. The query
+// carries a query=<name> comment, so slow query hooks, the process list and
+// the server logs attribute it.
+func (d *DB) Run(name string, args map[string]interface{}) ([]map[string]interface{}, error) {
+	return d.RunContext(context.Background(), name, args)
+}
+
+// RunContext is Run with a context.
+func (d *DB) RunContext(ctx context.Context, name string, args map[string]interface{}) ([]map[string]interface{}, error) {
+	qb, err := d.SavedQuery(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.WithContext(ctx).Get()
+}
+
+// SavedQuery returns the builder of the saved query name for args, for
+// running it some other way than Get (First, Count, ...).
+func (d *DB) SavedQuery(name string, args map[string]interface{}) (*QueryBuilder, error) {
+	saved, ok := d.savedQueries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownQuery, name)
+	}
+
+	checked, err := saved.check(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := saved.factory(d, checked)
+	if qb == nil {
+		return nil, fmt.Errorf("saved query %s: factory returned no builder", name)
+	}
+
+	return qb.Comment("query=" + name), nil
+}
+
+// check validates args against the declared parameters and fills in
+// defaults. Undeclared arguments are rejected, which catches typos.
+func (s savedQuery) check(name string, args map[string]interface{}) (Args, error) {
+	checked := make(Args, len(s.params))
+	declared := make(map[string]bool, len(s.params))
+	for _, param := range s.params {
+		declared[param.Name] = true
+
+		value, ok := args[param.Name]
+		if !ok {
+			if param.Required {
+				return nil, fmt.Errorf("saved query %s: missing parameter %s", name, param.Name)
+			}
+			checked[param.Name] = param.Default
+			continue
+		}
+
+		if param.Type != nil && value != nil {
+			v := reflect.ValueOf(value)
+			switch {
+			case v.Type() == param.Type:
+			case isNumber(v.Kind()) && isNumber(param.Type.Kind()):
+				if !fitsNumber(v, param.Type) {
+					return nil, fmt.Errorf("saved query %s: parameter %s is %v, which does not fit %s", name, param.Name, value, param.Type)
+				}
+				value = v.Convert(param.Type).Interface()
+			default:
+				return nil, fmt.Errorf("saved query %s: parameter %s is %T, want %s", name, param.Name, value, param.Type)
+			}
+		}
+		checked[param.Name] = value
+	}
+
+	for arg := range args {
+		if !declared[arg] {
+			return nil, fmt.Errorf("saved query %s: unknown parameter %s", name, arg)
+		}
+	}
+
+	return checked, nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// fitsNumber reports whether v converts to t without losing its value: a
+// fraction, a sign or digits beyond the range of t are all rejected, so 2.5
+// is not silently passed on as 2.
+func fitsNumber(v reflect.Value, t reflect.Type) bool {
+	target := reflect.New(t).Elem()
+	switch kind := v.Kind(); {
+	case isSigned(kind):
+		n := v.Int()
+		switch {
+		case isSigned(t.Kind()):
+			return !target.OverflowInt(n)
+		case isUnsigned(t.Kind()):
+			return n >= 0 && !target.OverflowUint(uint64(n))
+		}
+	case isUnsigned(kind):
+		n := v.Uint()
+		switch {
+		case isSigned(t.Kind()):
+			return n <= math.MaxInt64 && !target.OverflowInt(int64(n))
+		case isUnsigned(t.Kind()):
+			return !target.OverflowUint(n)
+		}
+	default:
+		f := v.Float()
+		switch {
+		case isSigned(t.Kind()):
+			// -2^63 and 2^63 are exact as floats; int64 holds the first only
+			return f == math.Trunc(f) && f >= math.MinInt64 && f < -math.MinInt64 && !target.OverflowInt(int64(f))
+		case isUnsigned(t.Kind()):
+			return f == math.Trunc(f) && f >= 0 && f < -2*math.MinInt64 && !target.OverflowUint(uint64(f))
+		}
+		return math.IsInf(f, 0) || math.IsNaN(f) || !target.OverflowFloat(f)
+	}
+
+	return true
+}
+
+func isSigned(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+// Args holds the checked arguments of a saved query. The accessors return
+// the zero value for absent arguments and arguments of another type.
+type Args map[string]interface{}
+
+// String returns the string argument name.
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+
+	return s
+}
+
+// Int returns the int argument name.
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+
+	return n
+}
+
+// Int64 returns the int64 argument name.
+func (a Args) Int64(name string) int64 {
+	n, _ := a[name].(int64)
+
+	return n
+}
+
+// Float64 returns the float64 argument name.
+func (a Args) Float64(name string) float64 {
+	f, _ := a[name].(float64)
+
+	return f
+}
+
+// Bool returns the bool argument name.
+func (a Args) Bool(name string) bool {
+	b, _ := a[name].(bool)
+
+	return b
+}
+
+// Time returns the time.Time argument name.
+func (a Args) Time(name string) time.Time {
+	t, _ := a[name].(time.Time)
+
+	return t
+}
+
+// Strings returns the []string argument name.
+func (a Args) Strings(name string) []string {
+	s, _ := a[name].([]string)
+
+	return s
+}
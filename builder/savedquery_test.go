@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSavedQueryNumberConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		example interface{}
+		arg     interface{}
+		want    interface{}
+		err     string
+	}{
+		{"whole float to int", 0, 3.0, 3, ""},
+		{"int to float", 0.0, 3, 3.0, ""},
+		{"int to int8", int8(0), 100, int8(100), ""},
+		{"uint to int", 0, uint(7), 7, ""},
+		{"fractional float to int", 0, 2.5, nil, "does not fit int"},
+		{"NaN to int", 0, math.NaN(), nil, "does not fit int"},
+		{"infinity to int64", int64(0), math.Inf(1), nil, "does not fit int64"},
+		{"float beyond int64", int64(0), 1e19, nil, "does not fit int64"},
+		{"int overflowing int8", int8(0), 300, nil, "does not fit int8"},
+		{"negative to uint", uint(0), -1, nil, "does not fit uint"},
+		{"negative float to uint", uint32(0), -2.0, nil, "does not fit uint32"},
+		{"uint beyond int64", int64(0), uint64(math.MaxUint64), nil, "does not fit int64"},
+		{"float beyond float32", float32(0), 1e300, nil, "does not fit float32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := savedQuery{params: []QueryParam{RequiredParam("n", tt.example)}}
+			args, err := saved.check("q", map[string]interface{}{"n": tt.arg})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if args["n"] != tt.want {
+				t.Errorf("n = %#v, want %#v", args["n"], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ScanFunc converts the raw bytes of a column value into the value Get
+// returns.
+type ScanFunc func(raw []byte) (interface{}, error)
+
+// RegisterScanner controls how matching columns materialize in Get, First
+// and the other map results. key is a column name, matched first, or a
+// database type name such as "DECIMAL", "BIT", "SET", "JSON" or "GEOMETRY":
+//
+//	db.RegisterScanner("DECIMAL", func(raw []byte) (interface{}, error) {
+//		return decimal.NewFromString(string(raw))
+//	})
+//
+// Scanners see the value after decryption; NULLs, and values the driver
+// already returns typed (integers and times from prepared statements), are
+// left alone.
+func (d *DB) RegisterScanner(key string, fn ScanFunc) *DB {
+	if d.scanners == nil {
+		d.scanners = make(map[string]ScanFunc)
+	}
+	d.scanners[key] = fn
+
+	return d
+}
+
+// columnScanners returns the scanner of each result column, or nil when no
+// column has one.
+func (qb *QueryBuilder) columnScanners(rows *sql.Rows) ([]string, []ScanFunc, error) {
+	if len(qb.db.scanners) == 0 {
+		return nil, nil, nil
+	}
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var columns []string
+	var scanners []ScanFunc
+	for i, t := range types {
+		fn, ok := qb.db.scanners[t.Name()]
+		if !ok {
+			fn, ok = qb.db.scanners[strings.ToUpper(t.DatabaseTypeName())]
+		}
+		if !ok {
+			continue
+		}
+		if scanners == nil {
+			columns = make([]string, len(types))
+			scanners = make([]ScanFunc, len(types))
+		}
+		columns[i], scanners[i] = t.Name(), fn
+	}
+
+	return columns, scanners, nil
+}
+
+// applyScanners converts the values of the columns with a scanner.
+func applyScanners(result []map[string]interface{}, columns []string, scanners []ScanFunc) error {
+	for _, row := range result {
+		for i, fn := range scanners {
+			if fn == nil {
+				continue
+			}
+			raw, ok := row[columns[i]].(string)
+			if !ok {
+				continue
+			}
+			value, err := fn([]byte(raw))
+			if err != nil {
+				return fmt.Errorf("scanning column %s: %w", columns[i], err)
+			}
+			row[columns[i]] = value
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// ReferentialAction is the ON DELETE / ON UPDATE behaviour of a foreign key.
+type ReferentialAction string
+
+// Referential actions for ForeignKey.OnDelete and ForeignKey.OnUpdate.
+const (
+	Restrict   ReferentialAction = "RESTRICT"
+	Cascade    ReferentialAction = "CASCADE"
+	SetNull    ReferentialAction = "SET NULL"
+	NoAction   ReferentialAction = "NO ACTION"
+	SetDefault ReferentialAction = "SET DEFAULT"
+)
+
+// ForeignKey describes a foreign key constraint. Name may be empty to let the
+// server pick one. Empty OnDelete/OnUpdate leave the server default.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   ReferentialAction
+	OnUpdate   ReferentialAction
+}
+
+// Column describes a column definition. Type is the MySQL column type as
+// written in DDL (e.g. "VARCHAR(64)"). When GeneratedAs is set the column is
+// GENERATED ALWAYS AS (GeneratedAs), either STORED or VIRTUAL. Type and
+// GeneratedAs are emitted verbatim and must not come from user input.
+type Column struct {
+	Name        string
+	Type        string
+	Nullable    bool
+	GeneratedAs string
+	Stored      bool
+}
+
+// Schema collects alterations to a table and applies them as a single
+// ALTER TABLE statement:
+//
+//	db.Schema("orders").
+//		AddForeignKey(builder.ForeignKey{Name: "fk_orders_user", Columns: []string{"user_id"},
+//			RefTable: "users", RefColumns: []string{"id"}, OnDelete: builder.Cascade}).
+//		AddIndex("idx_orders_created", "created_at").
+//		Exec()
+//
+// Invalid names are reported by SQL and Exec.
+type Schema struct {
+	db      *DB
+	table   string
+	clauses []string
+	err     error
+
+	// partition is the partitioning clause; maintenance marks one (ADD,
+	// DROP, ... PARTITION) that MySQL does not allow next to other changes.
+	partition   string
+	maintenance bool
+
+	// features are the server features the statement relies on.
+	features []Feature
+}
+
+// Schema starts a set of alterations to table.
+func (d *DB) Schema(table string) *Schema {
+	s := &Schema{db: d, table: table}
+	if !utils.IsValidIdentifier(table) {
+		s.setError(fmt.Errorf("invalid table name: %q", table))
+	}
+
+	return s
+}
+
+func (s *Schema) setError(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Schema) add(clause string) *Schema {
+	s.clauses = append(s.clauses, clause)
+
+	return s
+}
+
+// identifiers validates and quotes a list of column names.
+func (s *Schema) identifiers(kind string, names []string) string {
+	if len(names) == 0 {
+		s.setError(fmt.Errorf("%s: no columns given", kind))
+	}
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		if !utils.IsValidIdentifier(name) {
+			s.setError(fmt.Errorf("%s: invalid column name: %q", kind, name))
+		}
+		quoted[i] = quoteQualified(name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func (s *Schema) name(kind, name string) string {
+	if !utils.IsValidIdentifier(name) {
+		s.setError(fmt.Errorf("%s: invalid name: %q", kind, name))
+	}
+
+	return quoteQualified(name)
+}
+
+func (s *Schema) action(action ReferentialAction) string {
+	switch action {
+	case Restrict, Cascade, SetNull, NoAction, SetDefault:
+		return string(action)
+	}
+	s.setError(fmt.Errorf("foreign key: invalid referential action: %q", action))
+
+	return ""
+}
+
+// AddForeignKey adds a foreign key constraint.
+func (s *Schema) AddForeignKey(fk ForeignKey) *Schema {
+	var clause strings.Builder
+	clause.WriteString("ADD ")
+	if fk.Name != "" {
+		clause.WriteString("CONSTRAINT " + s.name("foreign key", fk.Name) + " ")
+	}
+	clause.WriteString("FOREIGN KEY (" + s.identifiers("foreign key", fk.Columns) + ")")
+	clause.WriteString(" REFERENCES " + s.name("foreign key", fk.RefTable))
+	clause.WriteString(" (" + s.identifiers("foreign key", fk.RefColumns) + ")")
+
+	if len(fk.Columns) != len(fk.RefColumns) {
+		s.setError(fmt.Errorf("foreign key: %d columns reference %d columns", len(fk.Columns), len(fk.RefColumns)))
+	}
+	if fk.OnDelete != "" {
+		clause.WriteString(" ON DELETE " + s.action(fk.OnDelete))
+	}
+	if fk.OnUpdate != "" {
+		clause.WriteString(" ON UPDATE " + s.action(fk.OnUpdate))
+	}
+
+	return s.add(clause.String())
+}
+
+// AddColumn adds a column, typically a generated one extracting a JSON field
+// so it can be indexed:
+//
+//	db.Schema("products").
+//		AddColumn(builder.Column{Name: "sku", Type: "VARCHAR(32)", Nullable: true, GeneratedAs: "data->>'$.sku'"}).
+//		AddIndex("idx_products_sku", "sku").
+//		Exec()
+func (s *Schema) AddColumn(col Column) *Schema {
+	if strings.TrimSpace(col.Type) == "" {
+		s.setError(fmt.Errorf("column %s: no type given", col.Name))
+	}
+
+	clause := "ADD COLUMN " + s.name("column", col.Name) + " " + col.Type
+	if col.GeneratedAs != "" {
+		clause += " GENERATED ALWAYS AS (" + col.GeneratedAs + ")"
+		if col.Stored {
+			clause += " STORED"
+		} else {
+			clause += " VIRTUAL"
+		}
+	}
+	if col.Nullable {
+		clause += " NULL"
+	} else {
+		clause += " NOT NULL"
+	}
+
+	return s.add(clause)
+}
+
+// DropColumn drops the named column.
+func (s *Schema) DropColumn(name string) *Schema {
+	return s.add("DROP COLUMN " + s.name("column", name))
+}
+
+// DropForeignKey drops the named foreign key constraint.
+func (s *Schema) DropForeignKey(name string) *Schema {
+	return s.add("DROP FOREIGN KEY " + s.name("foreign key", name))
+}
+
+// AddUnique adds a unique index over columns.
+func (s *Schema) AddUnique(name string, columns ...string) *Schema {
+	return s.add("ADD UNIQUE INDEX " + s.name("unique index", name) + " (" + s.identifiers("unique index", columns) + ")")
+}
+
+// AddIndex adds a plain index over columns.
+func (s *Schema) AddIndex(name string, columns ...string) *Schema {
+	return s.add("ADD INDEX " + s.name("index", name) + " (" + s.identifiers("index", columns) + ")")
+}
+
+// AddFunctionalIndex adds an index over expressions rather than columns
+// (MySQL 8.0.13+), e.g. over a JSON field without a generated column:
+//
+//	AddFunctionalIndex("idx_products_sku", "CAST(data->>'$.sku' AS CHAR(32)) COLLATE utf8mb4_bin")
+//
+// The expressions are emitted verbatim and must not come from user input.
+func (s *Schema) AddFunctionalIndex(name string, exprs ...string) *Schema {
+	if len(exprs) == 0 {
+		s.setError(fmt.Errorf("functional index: no expressions given"))
+	}
+
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = "(" + expr + ")"
+	}
+	s.features = append(s.features, FeatureFunctionalIndex)
+
+	return s.add("ADD INDEX " + s.name("functional index", name) + " (" + strings.Join(parts, ", ") + ")")
+}
+
+// DropIndex drops the named index, unique or not.
+func (s *Schema) DropIndex(name string) *Schema {
+	return s.add("DROP INDEX " + s.name("index", name))
+}
+
+// SQL returns the ALTER TABLE statement.
+func (s *Schema) SQL() (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if len(s.clauses) == 0 && s.partition == "" {
+		return "", fmt.Errorf("alter table %s: no changes", s.table)
+	}
+	if s.maintenance && len(s.clauses) > 0 {
+		return "", fmt.Errorf("alter table %s: partition maintenance cannot be combined with other changes", s.table)
+	}
+
+	query := "ALTER TABLE " + quoteQualified(s.table)
+	if len(s.clauses) > 0 {
+		query += " " + strings.Join(s.clauses, ", ")
+	}
+	if s.partition != "" {
+		query += " " + s.partition
+	}
+
+	return query, nil
+}
+
+// Exec runs the ALTER TABLE statement.
+func (s *Schema) Exec() (sql.Result, error) {
+	return s.ExecContext(context.Background())
+}
+
+// ExecContext runs the ALTER TABLE statement with the given context.
+func (s *Schema) ExecContext(ctx context.Context) (sql.Result, error) {
+	query, err := s.SQL()
+	if err != nil {
+		return nil, err
+	}
+	for _, feature := range s.features {
+		if err := s.db.require(ctx, feature); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.db.Table(s.table).WithContext(ctx).exec(query, nil, nil)
+}
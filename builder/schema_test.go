@@ -0,0 +1,18 @@
+package builder
+
+import "testing"
+
+func TestSchemaQuotesQualifiedNames(t *testing.T) {
+	query, err := testDB(t).Schema("app.orders").
+		AddForeignKey(ForeignKey{Name: "fk_orders_user", Columns: []string{"user_id"},
+			RefTable: "auth.users", RefColumns: []string{"id"}}).
+		SQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "ALTER TABLE `app`.`orders` ADD CONSTRAINT `fk_orders_user` FOREIGN KEY (`user_id`) REFERENCES `auth`.`users` (`id`)"
+	if query != want {
+		t.Errorf("SQL() = %s\nwant    %s", query, want)
+	}
+}
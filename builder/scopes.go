@@ -0,0 +1,35 @@
+package builder
+
+import "fmt"
+
+// ScopeFunc applies a reusable set of clauses to a builder.
+type ScopeFunc func(qb *QueryBuilder) *QueryBuilder
+
+// Scope applies fn to the builder, for filters shared between queries.
+func (qb *QueryBuilder) Scope(fn ScopeFunc) *QueryBuilder {
+	return fn(qb)
+}
+
+// RegisterScope names a scope so builders can apply it with Scoped.
+func (d *DB) RegisterScope(name string, fn ScopeFunc) *DB {
+	if d.scopes == nil {
+		d.scopes = make(map[string]ScopeFunc)
+	}
+	d.scopes[name] = fn
+
+	return d
+}
+
+// Scoped applies the registered scopes in order.
+func (qb *QueryBuilder) Scoped(names ...string) *QueryBuilder {
+	for _, name := range names {
+		fn, ok := qb.db.scopes[name]
+		if !ok {
+			qb.setError(fmt.Errorf("unknown scope: %s", name))
+			return qb
+		}
+		qb = fn(qb)
+	}
+
+	return qb
+}
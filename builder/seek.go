@@ -0,0 +1,103 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// OrderSpec is one column of a multi-column sort.
+type OrderSpec struct {
+	Column string
+	Desc   bool
+}
+
+// Asc sorts column in ascending order.
+func Asc(column string) OrderSpec { return OrderSpec{Column: column} }
+
+// Desc sorts column in descending order.
+func Desc(column string) OrderSpec { return OrderSpec{Column: column, Desc: true} }
+
+// SeekAfter orders by order and, when last is not nil, keeps only the rows
+// after last, the sort values of the final row of the previous page. This is
+// keyset pagination: unlike Offset it does not rescan skipped rows, and rows
+// inserted meanwhile do not shift pages. End order with a unique column (the
+// primary key) so ties are broken, and keep the sort columns NOT NULL.
+//
+//	qb.SeekAfter([]builder.OrderSpec{builder.Desc("created_at"), builder.Asc("id")}, last).Limit(50)
+//	// WHERE ((created_at < ?) OR (created_at = ? AND id > ?)) ORDER BY created_at DESC, id ASC
+//
+// When every column sorts the same way the condition is a row-value
+// comparison, (a, b) > (?, ?), which MySQL can use as an index range.
+func (qb *QueryBuilder) SeekAfter(order []OrderSpec, last []interface{}) *QueryBuilder {
+	if len(order) == 0 {
+		qb.setError(fmt.Errorf("SeekAfter: no order columns given"))
+		return qb
+	}
+
+	sameDirection := true
+	columns := make([]string, len(order))
+	sorts := make([]string, len(order))
+	for i, spec := range order {
+		if !utils.IsValidIdentifier(spec.Column) {
+			qb.setError(fmt.Errorf("invalid column name: %q", spec.Column))
+			return qb
+		}
+		columns[i] = spec.Column
+		sorts[i] = spec.Column + " " + spec.direction()
+		if spec.Desc != order[0].Desc {
+			sameDirection = false
+		}
+	}
+	qb.OrderBy(strings.Join(sorts, ", "))
+
+	if last == nil {
+		return qb
+	}
+	if len(last) != len(order) {
+		qb.setError(fmt.Errorf("SeekAfter: %d values for %d order columns", len(last), len(order)))
+		return qb
+	}
+
+	if sameDirection {
+		for i, column := range columns {
+			qb.bind(column, last[i])
+		}
+		qb.where = append(qb.where, "("+strings.Join(columns, ", ")+") "+order[0].after()+" ("+placeholders(len(last))+")")
+		return qb
+	}
+
+	// (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND c > ?) ...
+	branches := make([]string, len(order))
+	for i, spec := range order {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			qb.bind(columns[j], last[j])
+		}
+		parts = append(parts, spec.Column+" "+spec.after()+" ?")
+		qb.bind(spec.Column, last[i])
+		branches[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+	qb.where = append(qb.where, "("+strings.Join(branches, " OR ")+")")
+
+	return qb
+}
+
+func (s OrderSpec) direction() string {
+	if s.Desc {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+// after is the comparison selecting the rows that sort after a value.
+func (s OrderSpec) after() string {
+	if s.Desc {
+		return "<"
+	}
+
+	return ">"
+}
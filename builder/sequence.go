@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+const defaultSequenceTable = "sequences"
+
+// SetSequenceTable changes the table NextSequence keeps its counters in
+// (sequences by default). The table needs a name column that is its primary
+// key and a BIGINT value column:
+//
+//	CREATE TABLE sequences (name VARCHAR(64) PRIMARY KEY, value BIGINT NOT NULL)
+func (d *DB) SetSequenceTable(table string) *DB {
+	d.sequenceTable = table
+
+	return d
+}
+
+// NextSequence increments the named counter and returns its new value,
+// starting at 1 for a counter that does not exist yet. The increment and
+// the read are a single statement, so concurrent callers never see the same
+// value. Numbers are only gapless when they are taken inside the transaction
+// that uses them (see Tx.NextSequence): a rollback then returns them.
+func (d *DB) NextSequence(name string) (int64, error) {
+	return d.nextSequence(d.Table(d.sequenceTableName()), name)
+}
+
+// NextSequence is DB.NextSequence inside the transaction.
+func (tx *Tx) NextSequence(name string) (int64, error) {
+	return tx.db.nextSequence(tx.Table(tx.db.sequenceTableName()), name)
+}
+
+func (d *DB) sequenceTableName() string {
+	if d.sequenceTable == "" {
+		return defaultSequenceTable
+	}
+
+	return d.sequenceTable
+}
+
+// nextSequence relies on LAST_INSERT_ID(expr), which both returns expr and
+// reports it as the insert id of the statement, so no second query on the
+// same connection is needed.
+func (d *DB) nextSequence(qb *QueryBuilder, name string) (int64, error) {
+	if !utils.IsValidIdentifier(qb.table) {
+		return 0, fmt.Errorf("invalid sequence table name: %q", qb.table)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (name, value) VALUES (?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + 1)", qb.table)
+	result, err := qb.exec(query, []interface{}{name}, []string{"name"})
+	if err != nil {
+		return 0, fmt.Errorf("error advancing sequence %s: %w", name, err)
+	}
+
+	return result.LastInsertId()
+}
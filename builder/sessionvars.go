@@ -0,0 +1,235 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+type sessionVar struct {
+	name  string
+	value interface{}
+}
+
+// SetSessionVar sets a session variable (sql_mode, group_concat_max_len,
+// foreign_key_checks, ...) for every builder of the DB. Like SessionVar, it
+// is applied around each statement and restored afterwards, so pooled
+// connections are left as they were found.
+func (d *DB) SetSessionVar(name string, value interface{}) *DB {
+	d.sessionVars = append(d.sessionVars, sessionVar{name: name, value: value})
+
+	return d
+}
+
+// GetVariable returns the value of a system variable as a connection of the
+// pool sees it (the session value, which defaults to the global one).
+func (d *DB) GetVariable(name string) (string, error) {
+	return d.Table("").GetVariable(name)
+}
+
+// SessionVar sets a session variable for the statements this builder runs,
+// overriding a DB-wide SetSessionVar of the same name:
+//
+//	db.Table("posts").SessionVar("group_concat_max_len", 1<<20).
+//		Select("author_id", "GROUP_CONCAT(title) AS titles").GroupBy("author_id").Get()
+//
+// On the pool, the statement runs on a dedicated connection with the
+// variables set, which are then put back to their previous values. If they
+// cannot be restored, the connection is discarded.
+func (qb *QueryBuilder) SessionVar(name string, value interface{}) *QueryBuilder {
+	qb.sessionVars = append(qb.sessionVars, sessionVar{name: name, value: value})
+
+	return qb
+}
+
+// GetVariable returns the value of a system variable on the builder's
+// connection, with the builder's session variables applied.
+func (qb *QueryBuilder) GetVariable(name string) (string, error) {
+	if !utils.IsValidIdentifier(name) {
+		return "", fmt.Errorf("invalid variable name: %q", name)
+	}
+
+	var value sql.NullString
+	if err := qb.scanOne("SELECT @@"+name, nil, &value); err != nil {
+		return "", err
+	}
+
+	return value.String, nil
+}
+
+// SetSessionVar sets a session variable on the session's connection. It
+// stays set until the session ends; the connection is then reset by
+// discarding it rather than returning it to the pool.
+func (s *Session) SetSessionVar(name string, value interface{}) error {
+	if !utils.IsValidIdentifier(name) {
+		return fmt.Errorf("invalid variable name: %q", name)
+	}
+	if _, err := s.ExecContext(context.Background(), "SET SESSION "+name+" = ?", value); err != nil {
+		return err
+	}
+	s.dirty = true
+
+	return nil
+}
+
+// GetVariable returns the value of a system variable on the session's
+// connection.
+func (s *Session) GetVariable(name string) (string, error) {
+	return s.Table("").GetVariable(name)
+}
+
+// effectiveSessionVars merges the DB and builder session variables, the
+// builder's winning, in first-set order.
+func (qb *QueryBuilder) effectiveSessionVars() []sessionVar {
+	if len(qb.db.sessionVars) == 0 {
+		return qb.sessionVars
+	}
+
+	vars := make([]sessionVar, 0, len(qb.db.sessionVars)+len(qb.sessionVars))
+	index := make(map[string]int)
+	for _, list := range [][]sessionVar{qb.db.sessionVars, qb.sessionVars} {
+		for _, v := range list {
+			key := strings.ToLower(v.name)
+			if i, ok := index[key]; ok {
+				vars[i] = v
+				continue
+			}
+			index[key] = len(vars)
+			vars = append(vars, v)
+		}
+	}
+
+	return vars
+}
+
+// connPinner is implemented by pools that can hand out a dedicated
+// connection (*sql.DB).
+type connPinner interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
+
+// withSessionVars runs fn with the builder's session variables set on its
+// connection, restoring the previous values afterwards. Without session
+// variables, or while they are already applied, fn runs as is.
+func (qb *QueryBuilder) withSessionVars(fn func() error) (err error) {
+	vars := qb.effectiveSessionVars()
+	if len(vars) == 0 || qb.sessionActive {
+		return fn()
+	}
+	if qb.err != nil {
+		return qb.err
+	}
+	for _, v := range vars {
+		if !utils.IsValidIdentifier(v.name) {
+			return fmt.Errorf("invalid variable name: %q", v.name)
+		}
+	}
+
+	ctx := qb.context()
+	runner := qb.runner
+	var conn *sql.Conn
+	if pool, ok := runner.(connPinner); ok {
+		if conn, err = pool.Conn(ctx); err != nil {
+			return err
+		}
+		qb.runner = conn
+	}
+
+	restore, err := qb.setSessionVars(ctx, vars)
+	qb.sessionActive = true
+	defer func() {
+		if restore != nil {
+			if _, restoreErr := qb.runner.ExecContext(ctx, restore.query, restore.params...); restoreErr != nil {
+				if conn != nil {
+					discard(conn)
+				} else if err == nil {
+					err = fmt.Errorf("restore session variables: %w", restoreErr)
+				}
+			}
+		}
+		qb.sessionActive = false
+		qb.runner = runner
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+type restoreStatement struct {
+	query  string
+	params []interface{}
+}
+
+// setSessionVars reads the current values of vars, then sets the new ones.
+// The returned statement puts the old values back; it is nil when nothing
+// was changed.
+func (qb *QueryBuilder) setSessionVars(ctx context.Context, vars []sessionVar) (*restoreStatement, error) {
+	selects := make([]string, len(vars))
+	sets := make([]string, len(vars))
+	params := make([]interface{}, len(vars))
+	for i, v := range vars {
+		selects[i] = "@@SESSION." + v.name
+		sets[i] = "SESSION " + v.name + " = ?"
+		params[i] = v.value
+	}
+
+	rows, err := qb.runner.QueryContext(ctx, "SELECT "+strings.Join(selects, ", "))
+	if err != nil {
+		return nil, err
+	}
+	old := make([]sql.NullString, len(vars))
+	dest := make([]interface{}, len(vars))
+	for i := range old {
+		dest[i] = &old[i]
+	}
+	if rows.Next() {
+		err = rows.Scan(dest...)
+	} else if err = rows.Err(); err == nil {
+		err = sql.ErrNoRows
+	}
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	restore := &restoreStatement{query: "SET " + strings.Join(sets, ", "), params: make([]interface{}, len(vars))}
+	for i, value := range old {
+		restore.params[i] = variableValue(value)
+	}
+
+	if _, err := qb.runner.ExecContext(ctx, "SET "+strings.Join(sets, ", "), params...); err != nil {
+		return restore, err
+	}
+
+	return restore, nil
+}
+
+// variableValue turns a value read back from @@SESSION into one SET accepts:
+// numeric variables as numbers, NULL as nil.
+func variableValue(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	if n, err := strconv.ParseInt(value.String, 10, 64); err == nil {
+		return n
+	}
+
+	return value.String
+}
+
+// discard makes the pool close conn instead of reusing it.
+func discard(conn *sql.Conn) {
+	conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+}
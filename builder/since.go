@@ -0,0 +1,246 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// SinceCursor is the position of an incremental read: the watermark of the
+// last row read and its primary key, which orders the rows sharing that
+// watermark.
+type SinceCursor struct {
+	Value interface{}
+	Key   interface{}
+}
+
+// Since restricts the query to rows past cursor in (column, primary key)
+// order and sorts them that way, for incremental reads by an updated_at or
+// auto-increment watermark. Because the key breaks ties, a batch can end in
+// the middle of rows sharing one watermark (say, a bulk UPDATE setting
+// updated_at = NOW()) and the next read picks up the rest.
+//
+// cursor is nil to read from the beginning, or a SinceCursor. A bare
+// watermark value, as saved before cursors carried the key, reads the rows
+// strictly past it.
+func (qb *QueryBuilder) Since(column string, cursor interface{}) *QueryBuilder {
+	if !utils.IsValidIdentifier(column) {
+		qb.setError(fmt.Errorf("invalid column name: %q", column))
+		return qb
+	}
+	key, err := qb.primaryKeyColumn()
+	if err != nil {
+		qb.setError(fmt.Errorf("Since needs a single-column key: %w", err))
+		return qb
+	}
+	order := []OrderSpec{Asc(column), Asc(key)}
+
+	switch c := cursor.(type) {
+	case nil:
+		return qb.SeekAfter(order, nil)
+	case SinceCursor:
+		return qb.SeekAfter(order, []interface{}{c.Value, c.Key})
+	case *SinceCursor:
+		return qb.SeekAfter(order, []interface{}{c.Value, c.Key})
+	default:
+		qb.Where(column, ">", cursor)
+		return qb.SeekAfter(order, nil)
+	}
+}
+
+// Checkpoints persists the cursor of each named Poller between runs.
+type Checkpoints interface {
+	// Load returns the saved cursor, or nil when there is none.
+	Load(ctx context.Context, name string) (interface{}, error)
+	Save(ctx context.Context, name string, cursor interface{}) error
+}
+
+// TableCheckpoints keeps cursors in a table of the DB:
+//
+//	CREATE TABLE sync_checkpoints (
+//		name         VARCHAR(191) PRIMARY KEY,
+//		cursor_value VARCHAR(255) NOT NULL,
+//		updated_at   DATETIME(6) NOT NULL
+//	);
+//
+// Cursors are stored as text, which MySQL compares correctly with both
+// DATETIME and integer columns; a SinceCursor is stored as a JSON array of
+// its two values.
+func (d *DB) TableCheckpoints(table string) Checkpoints {
+	return &tableCheckpoints{db: d, table: table}
+}
+
+type tableCheckpoints struct {
+	db    *DB
+	table string
+}
+
+func (c *tableCheckpoints) Load(ctx context.Context, name string) (interface{}, error) {
+	if !utils.IsValidIdentifier(c.table) {
+		return nil, fmt.Errorf("invalid table name: %q", c.table)
+	}
+
+	var cursor string
+	err := c.db.Table(c.table).WithContext(ctx).
+		scanOne("SELECT cursor_value FROM "+c.table+" WHERE name = ?", []interface{}{name}, &cursor)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pair []string
+	if strings.HasPrefix(cursor, "[") && json.Unmarshal([]byte(cursor), &pair) == nil && len(pair) == 2 {
+		return SinceCursor{Value: pair[0], Key: pair[1]}, nil
+	}
+
+	return cursor, nil
+}
+
+func (c *tableCheckpoints) Save(ctx context.Context, name string, cursor interface{}) error {
+	if !utils.IsValidIdentifier(c.table) {
+		return fmt.Errorf("invalid table name: %q", c.table)
+	}
+
+	value := cursorText(cursor)
+	if c, ok := cursor.(SinceCursor); ok {
+		encoded, err := json.Marshal([]string{cursorText(c.Value), cursorText(c.Key)})
+		if err != nil {
+			return err
+		}
+		value = string(encoded)
+	}
+
+	query := "INSERT INTO " + c.table + " (name, cursor_value, updated_at) VALUES (?, ?, ?)" +
+		" ON DUPLICATE KEY UPDATE cursor_value = VALUES(cursor_value), updated_at = VALUES(updated_at)"
+	_, err := c.db.Table(c.table).WithContext(ctx).exec(query, []interface{}{name, value, time.Now().UTC()}, []string{"name", "cursor_value", "updated_at"})
+
+	return err
+}
+
+func cursorText(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format("2006-01-02 15:04:05.999999")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Poller repeatedly reads the rows added or changed since its last poll,
+// using Since on Column, and hands them to Handle in batches. The cursor is
+// saved to Checkpoints after each handled batch, so a restarted poller picks
+// up where it stopped; a batch whose Handle fails is read again.
+//
+// The cursor is a SinceCursor, so Query must select the table's primary key.
+// With an updated_at watermark, a row committed after a batch is read but
+// stamped no later than that batch's last row is skipped; use a DATETIME(6)
+// column, or an auto-increment id for append-only tables.
+type Poller struct {
+	Name        string
+	Column      string
+	Query       func() *QueryBuilder
+	Handle      func(ctx context.Context, rows []map[string]interface{}) error
+	Checkpoints Checkpoints
+
+	// Batch is the number of rows read per poll (default 500).
+	Batch int
+
+	// Interval is the pause after a poll that found nothing (default 5s).
+	Interval time.Duration
+
+	// OnError, when set, is told about failed polls; Run keeps going
+	// either way.
+	OnError func(err error)
+
+	cursor interface{}
+	loaded bool
+}
+
+// NewPoller returns a Poller named name that reads query() by column. Without
+// Checkpoints set, the cursor only lives in memory.
+func NewPoller(name, column string, query func() *QueryBuilder, handle func(ctx context.Context, rows []map[string]interface{}) error) *Poller {
+	return &Poller{
+		Name:     name,
+		Column:   column,
+		Query:    query,
+		Handle:   handle,
+		Batch:    500,
+		Interval: 5 * time.Second,
+	}
+}
+
+// Run polls until ctx is cancelled and returns ctx.Err(). Full batches are
+// followed by another poll straight away.
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		n, err := p.Poll(ctx)
+		if err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+
+		if n < p.Batch || err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(p.Interval):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll reads and handles one batch and returns its size.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	if !p.loaded && p.Checkpoints != nil {
+		cursor, err := p.Checkpoints.Load(ctx, p.Name)
+		if err != nil {
+			return 0, fmt.Errorf("poller %s: load checkpoint: %w", p.Name, err)
+		}
+		p.cursor = cursor
+	}
+	p.loaded = true
+
+	qb := p.Query()
+	key, err := qb.primaryKeyColumn()
+	if err != nil {
+		return 0, fmt.Errorf("poller %s: %w", p.Name, err)
+	}
+	rows, err := qb.WithContext(ctx).Since(p.Column, p.cursor).Limit(p.Batch).Get()
+	if err != nil {
+		return 0, fmt.Errorf("poller %s: %w", p.Name, err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	last := rows[len(rows)-1]
+	cursor := SinceCursor{Value: last[unqualified(p.Column)], Key: last[unqualified(key)]}
+	if cursor.Key == nil {
+		return 0, fmt.Errorf("poller %s: rows lack the key column %s", p.Name, key)
+	}
+
+	if err := p.Handle(ctx, rows); err != nil {
+		return 0, fmt.Errorf("poller %s: handle: %w", p.Name, err)
+	}
+
+	if p.Checkpoints != nil {
+		if err := p.Checkpoints.Save(ctx, p.Name, cursor); err != nil {
+			return len(rows), fmt.Errorf("poller %s: save checkpoint: %w", p.Name, err)
+		}
+	}
+	p.cursor = cursor
+
+	return len(rows), nil
+}
+
+// unqualified strips the table from a column name, as result rows key it.
+func unqualified(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSinceBreaksTiesOnTheKey(t *testing.T) {
+	d := testDB(t)
+
+	tests := []struct {
+		name       string
+		cursor     interface{}
+		wantSQL    string
+		wantParams []interface{}
+	}{
+		{"from the beginning", nil,
+			"SELECT * FROM events ORDER BY updated_at ASC, id ASC", nil},
+		{"cursor with key", SinceCursor{Value: "2024-01-01 00:00:00", Key: 42},
+			"SELECT * FROM events WHERE (updated_at, id) > (?, ?) ORDER BY updated_at ASC, id ASC",
+			[]interface{}{"2024-01-01 00:00:00", 42}},
+		{"bare watermark", "2024-01-01 00:00:00",
+			"SELECT * FROM events WHERE updated_at > ? ORDER BY updated_at ASC, id ASC",
+			[]interface{}{"2024-01-01 00:00:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params, err := d.Table("events").Since("updated_at", tt.cursor).ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantSQL || !reflect.DeepEqual(params, tt.wantParams) {
+				t.Fatalf("got %q %v\nwant %q %v", query, params, tt.wantSQL, tt.wantParams)
+			}
+		})
+	}
+}
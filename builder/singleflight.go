@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EnableSingleflight makes concurrent identical reads (same SQL, parameters,
+// session variables, row limit and connection pool) share one database round
+// trip. Each caller gets its own copy of the row maps, Truncated flag and
+// Capture result. The shared read runs without the callers' cancellation; a
+// caller whose context ends stops waiting for it. Reads inside transactions
+// are never shared.
+func (d *DB) EnableSingleflight() *DB {
+	d.singleflight.enabled = true
+
+	return d
+}
+
+type flightCall struct {
+	done      chan struct{}
+	rows      []map[string]interface{}
+	err       error
+	truncated bool
+	captured  Result
+}
+
+type flightGroup struct {
+	enabled bool
+	mu      sync.Mutex
+	calls   map[string]*flightCall
+}
+
+// do runs fn once for all concurrent callers with the same key. fn runs in
+// its own goroutine, so a caller whose ctx ends returns ctx.Err() right away
+// and the others still get the result.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(call *flightCall)) (*flightCall, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go func() {
+			defer func() {
+				g.mu.Lock()
+				delete(g.calls, key)
+				g.mu.Unlock()
+				close(call.done)
+			}()
+			fn(call)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of its parent but not its cancellation or
+// deadline, for a shared read that must outlive the caller that started it.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+func copyRows(rows []map[string]interface{}) []map[string]interface{} {
+	if rows == nil {
+		return nil
+	}
+
+	copied := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		clone := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			clone[column] = value
+		}
+		copied[i] = clone
+	}
+
+	return copied
+}
@@ -0,0 +1,100 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFlightKey(t *testing.T) {
+	d := testDB(t)
+	pool := &sql.DB{}
+	at := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	params := []interface{}{at}
+	key := func(qb *QueryBuilder) string { return qb.flightKey(pool, "SELECT * FROM events WHERE at > ?", params) }
+
+	base := key(d.Table("events"))
+	if key(d.Table("events")) != base {
+		t.Error("identical reads got different keys")
+	}
+	for name, qb := range map[string]*QueryBuilder{
+		"Timezone":   d.Table("events").Timezone("Asia/Dhaka"),
+		"SessionVar": d.Table("events").SessionVar("sql_mode", "ANSI"),
+		"MaxRows":    d.Table("events").MaxRows(10, TruncateOnMaxRows),
+	} {
+		if key(qb) == base {
+			t.Errorf("%s: shares the key of a plain read", name)
+		}
+	}
+}
+
+func TestFlightFollowersOutliveLeader(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(leaderCtx, "k", func(call *flightCall) {
+			close(started)
+			<-release
+			call.rows = []map[string]interface{}{{"id": 1}}
+			call.truncated = true
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	followed := make(chan *flightCall, 1)
+	go func() {
+		call, err := g.do(context.Background(), "k", func(*flightCall) { t.Error("follower ran its own fetch") })
+		if err != nil {
+			t.Error(err)
+		}
+		followed <- call
+	}()
+	time.Sleep(20 * time.Millisecond) // let the follower join
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader: got %v, want context.Canceled", err)
+	}
+
+	close(release)
+	call := <-followed
+	if call == nil || len(call.rows) != 1 || !call.truncated {
+		t.Errorf("follower got %+v", call)
+	}
+}
+
+func TestFlightFollowerCancel(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	defer close(release)
+
+	go g.do(context.Background(), "k", func(*flightCall) { <-release })
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.do(ctx, "k", func(*flightCall) {}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestDetachedContext(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "v"))
+	cancel()
+
+	ctx := detachedContext{parent}
+	if ctx.Err() != nil || ctx.Done() != nil {
+		t.Error("detached context is cancelled with its parent")
+	}
+	if ctx.Value(key{}) != "v" {
+		t.Error("detached context lost its parent's values")
+	}
+}
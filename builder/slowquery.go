@@ -0,0 +1,71 @@
+package builder
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// QueryInfo describes an executed statement.
+type QueryInfo struct {
+	SQL      string
+	Params   []interface{}
+	Duration time.Duration
+	Caller   string // file:line of the first caller outside this package
+
+	// Origins are the builder's clause locations when Debug is on.
+	Origins []string
+}
+
+type slowQueryHook struct {
+	threshold time.Duration
+	fn        func(info QueryInfo)
+}
+
+// OnSlowQuery calls fn for every statement whose execution takes at least
+// threshold. For reads the duration covers the round trip until rows are
+// available, not the time spent iterating them.
+func (d *DB) OnSlowQuery(threshold time.Duration, fn func(info QueryInfo)) *DB {
+	d.slowQueryHooks = append(d.slowQueryHooks, slowQueryHook{threshold: threshold, fn: fn})
+
+	return d
+}
+
+// observe reports a finished statement to the slow query hooks.
+func (qb *QueryBuilder) observe(query string, params []interface{}, columns []string, start time.Time) {
+	if len(qb.db.slowQueryHooks) == 0 {
+		return
+	}
+
+	elapsed := time.Since(start)
+	var info *QueryInfo
+	for _, hook := range qb.db.slowQueryHooks {
+		if elapsed < hook.threshold {
+			continue
+		}
+		if info == nil {
+			info = &QueryInfo{SQL: query, Params: qb.db.maskParams(params, columns), Duration: elapsed, Caller: callerOutsidePackage(), Origins: qb.Origins()}
+		}
+		hook.fn(*info)
+	}
+}
+
+const packagePrefix = "github.com/ruhulfbr/go-mysql-qb/builder."
+
+// callerOutsidePackage returns the file:line of the first stack frame that
+// does not belong to the builder package.
+func callerOutsidePackage() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
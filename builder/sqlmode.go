@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotStrict is returned by RequireStrictMode when the server would
+// silently truncate or coerce invalid data instead of rejecting it.
+var ErrNotStrict = errors.New("builder: sql_mode is not strict")
+
+// SQLMode returns the modes in the sql_mode of a connection of the pool.
+func (d *DB) SQLMode() ([]string, error) {
+	return d.SQLModeContext(context.Background())
+}
+
+// SQLModeContext is SQLMode with a context.
+func (d *DB) SQLModeContext(ctx context.Context) ([]string, error) {
+	mode, err := d.Table("").WithContext(ctx).GetVariable("sql_mode")
+	if err != nil {
+		return nil, fmt.Errorf("read sql_mode: %w", err)
+	}
+	if mode == "" {
+		return nil, nil
+	}
+
+	return strings.Split(strings.ToUpper(mode), ","), nil
+}
+
+// StrictMode reports whether sql_mode includes STRICT_TRANS_TABLES or
+// STRICT_ALL_TABLES, under which MySQL rejects out-of-range values, too long
+// strings and invalid dates instead of storing an adjusted value with a
+// warning.
+func (d *DB) StrictMode() (bool, error) {
+	return d.StrictModeContext(context.Background())
+}
+
+// StrictModeContext is StrictMode with a context.
+func (d *DB) StrictModeContext(ctx context.Context) (bool, error) {
+	modes, err := d.SQLModeContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return isStrict(modes), nil
+}
+
+func isStrict(modes []string) bool {
+	for _, mode := range modes {
+		if mode == "STRICT_TRANS_TABLES" || mode == "STRICT_ALL_TABLES" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequireStrictMode returns ErrNotStrict, with the current sql_mode, unless
+// the server runs in strict mode. Call it at startup to fail fast rather than
+// have data corrupted silently.
+func (d *DB) RequireStrictMode() error {
+	return d.RequireStrictModeContext(context.Background())
+}
+
+// RequireStrictModeContext is RequireStrictMode with a context.
+func (d *DB) RequireStrictModeContext(ctx context.Context) error {
+	modes, err := d.SQLModeContext(ctx)
+	if err != nil {
+		return err
+	}
+	if isStrict(modes) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: sql_mode=%q; add STRICT_TRANS_TABLES", ErrNotStrict, strings.Join(modes, ","))
+}
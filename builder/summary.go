@@ -0,0 +1,91 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// RefreshMode selects how RefreshSummaryTable updates the summary table.
+type RefreshMode int
+
+const (
+	// RefreshReplace empties the table and refills it, so rows the source
+	// no longer produces disappear.
+	RefreshReplace RefreshMode = iota
+	// RefreshMerge upserts the source rows by the table's primary or unique
+	// key and leaves other rows alone; cheaper for large tables refreshed
+	// from a recent slice of data.
+	RefreshMerge
+)
+
+// RefreshSummaryTable rebuilds target, a denormalized reporting table, from
+// the rows of source, inside one transaction so readers see either the old
+// or the new contents. source must select target's columns in table order:
+//
+//	daily := db.Table("orders").
+//		Select("DATE(created_at) AS day", "COUNT(*) AS orders", "SUM(total) AS revenue").
+//		GroupBy("DATE(created_at)")
+//	n, err := db.RefreshSummaryTable("daily_sales", daily, builder.RefreshReplace)
+//
+// It returns the affected-rows count of the INSERT. With RefreshReplace that
+// is the number of rows written. With RefreshMerge it is MySQL's count for
+// ON DUPLICATE KEY UPDATE: 1 per inserted row, 2 per updated row and 0 per
+// row that already held the same values, so it is not a row count. Replace
+// uses DELETE rather than TRUNCATE, which would commit the transaction
+// early.
+func (d *DB) RefreshSummaryTable(target string, source *QueryBuilder, mode RefreshMode) (int64, error) {
+	if !utils.IsValidIdentifier(target) {
+		return 0, fmt.Errorf("invalid table name: %q", target)
+	}
+	if source.err != nil {
+		return 0, source.err
+	}
+
+	qb := d.Table(target).WithContext(source.context()).WithTx(source.txOptions...)
+	if source.db == d {
+		qb.runner = source.runner
+	}
+
+	var written int64
+	err := qb.inTransaction(func() error {
+		selectSQL, params := source.Build()
+		insert := fmt.Sprintf("INSERT INTO %s %s", target, selectSQL)
+
+		switch mode {
+		case RefreshReplace:
+			if _, err := qb.exec("DELETE FROM "+target, nil, nil); err != nil {
+				return err
+			}
+		case RefreshMerge:
+			columns, err := d.tableColumns(qb.context(), target)
+			if err != nil {
+				return err
+			}
+			if len(columns) == 0 {
+				return fmt.Errorf("table %s not found", target)
+			}
+			updates := make([]string, len(columns))
+			for i, column := range columns {
+				updates[i] = fmt.Sprintf("%s = VALUES(%s)", column, column)
+			}
+			insert += " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+		default:
+			return fmt.Errorf("unknown refresh mode: %d", mode)
+		}
+
+		result, err := qb.exec(insert, params, nil)
+		if err != nil {
+			return err
+		}
+		written, err = result.RowsAffected()
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error refreshing %s: %w", target, err)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+)
+
+// TableStat is the size report of one table from information_schema.TABLES.
+// Rows is an estimate for InnoDB tables; AutoIncrement is 0 for tables
+// without an AUTO_INCREMENT column.
+type TableStat struct {
+	Name          string
+	Engine        string
+	Rows          int64
+	DataBytes     int64
+	IndexBytes    int64
+	AutoIncrement uint64
+}
+
+// TotalBytes returns the data and index size together.
+func (s TableStat) TotalBytes() int64 {
+	return s.DataBytes + s.IndexBytes
+}
+
+// TableStats reports the size of the base tables in the current database,
+// largest first, or of the given tables only. The figures are as fresh as
+// the server's statistics; run AnalyzeTable first when they must be current.
+func (d *DB) TableStats(tables ...string) ([]TableStat, error) {
+	return d.TableStatsContext(context.Background(), tables...)
+}
+
+// TableStatsContext is TableStats with a context.
+func (d *DB) TableStatsContext(ctx context.Context, tables ...string) ([]TableStat, error) {
+	query := "SELECT TABLE_NAME, ENGINE, TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH, AUTO_INCREMENT" +
+		" FROM information_schema.TABLES" +
+		" WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
+
+	params := make([]interface{}, len(tables))
+	for i, table := range tables {
+		params[i] = table
+	}
+	if len(tables) > 0 {
+		query += " AND TABLE_NAME IN (" + placeholders(len(tables)) + ")"
+	}
+	query += " ORDER BY DATA_LENGTH + INDEX_LENGTH DESC, TABLE_NAME"
+
+	rows, err := d.Table("information_schema.TABLES").WithContext(ctx).query(query, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []TableStat
+	for rows.Next() {
+		var s TableStat
+		var engine sql.NullString
+		var count, data, index, autoIncrement sql.NullInt64
+		if err := rows.Scan(&s.Name, &engine, &count, &data, &index, &autoIncrement); err != nil {
+			return nil, err
+		}
+		s.Engine = engine.String
+		s.Rows, s.DataBytes, s.IndexBytes = count.Int64, data.Int64, index.Int64
+		s.AutoIncrement = uint64(autoIncrement.Int64)
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// Session is a single connection checked out of the pool. TEMPORARY tables
+// and session variables only live on the connection that created them, so
+// multi-step work that relies on them runs inside a Session.
+type Session struct {
+	*sql.Conn
+	db         *DB
+	tempTables []string
+	dirty      bool // session variables were changed
+}
+
+// WithSession runs fn on a dedicated connection. Temporary tables created
+// with Session.CreateTempTableAs are dropped before the connection goes back
+// to the pool.
+func (d *DB) WithSession(ctx context.Context, fn func(s *Session) error) (err error) {
+	conn, err := d.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := &Session{Conn: conn, db: d}
+	defer func() {
+		if dropErr := s.dropTempTables(ctx); dropErr != nil && err == nil {
+			err = dropErr
+		}
+		if s.dirty {
+			discard(conn)
+		}
+		conn.Close()
+	}()
+
+	return fn(s)
+}
+
+// Table starts a new query against table on the session's connection.
+func (s *Session) Table(table string) *QueryBuilder {
+	return s.db.Table(table).UseConnection(s.Conn)
+}
+
+// CreateTempTableAs stages the result of qb in a TEMPORARY table on the
+// session's connection, whatever connection qb was built for:
+//
+//	d.WithSession(ctx, func(s *builder.Session) error {
+//		if _, err := s.CreateTempTableAs("active_users", s.Table("users").Where("active", "=", 1)); err != nil {
+//			return err
+//		}
+//		rows, err := s.Table("active_users").Get()
+//		...
+//	})
+func (s *Session) CreateTempTableAs(name string, qb *QueryBuilder) (*TempTable, error) {
+	t, err := s.db.CreateTempTableAs(name, qb.UseConnection(s.Conn))
+	if err != nil {
+		return nil, err
+	}
+	s.tempTables = append(s.tempTables, name)
+
+	return t, nil
+}
+
+func (s *Session) dropTempTables(ctx context.Context) error {
+	for len(s.tempTables) > 0 {
+		name := s.tempTables[len(s.tempTables)-1]
+		if _, err := s.ExecContext(ctx, "DROP TEMPORARY TABLE IF EXISTS "+quoteIdentifier(name)); err != nil {
+			return fmt.Errorf("drop temporary table %s: %w", name, err)
+		}
+		s.tempTables = s.tempTables[:len(s.tempTables)-1]
+	}
+
+	return nil
+}
+
+// TempTable is a TEMPORARY table created from a query.
+type TempTable struct {
+	Name   string
+	db     *DB
+	runner Runner
+}
+
+// CreateTempTableAs runs CREATE TEMPORARY TABLE name AS <qb> on qb's
+// connection. The table is only visible on that connection, so qb should run
+// on a Session, a Tx or a *sql.Conn rather than the pool; Session.CreateTempTableAs
+// takes care of that and of dropping the table.
+func (d *DB) CreateTempTableAs(name string, qb *QueryBuilder) (*TempTable, error) {
+	if !utils.IsValidIdentifier(name) {
+		return nil, fmt.Errorf("invalid table name: %q", name)
+	}
+
+	query, params := qb.Build()
+	query = "CREATE TEMPORARY TABLE " + quoteIdentifier(name) + " AS " + query
+	if _, err := qb.exec(query, params, qb.boundColumns()); err != nil {
+		return nil, err
+	}
+
+	return &TempTable{Name: name, db: d, runner: qb.runner}, nil
+}
+
+// Table starts a new query against the temporary table on the connection
+// that holds it.
+func (t *TempTable) Table() *QueryBuilder {
+	return t.db.Table(t.Name).UseConnection(t.runner)
+}
+
+// Drop drops the temporary table.
+func (t *TempTable) Drop() error {
+	_, err := t.Table().exec("DROP TEMPORARY TABLE IF EXISTS "+quoteIdentifier(t.Name), nil, nil)
+
+	return err
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoTenant is returned for queries on a tenant-scoped table whose context
+// carries no tenant and that did not opt out with WithoutTenant.
+var ErrNoTenant = errors.New("builder: no tenant in context for tenant-scoped table")
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant that scopes queries on
+// tables registered with EnableTenancy.
+func WithTenant(ctx context.Context, tenantID interface{}) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant stored by WithTenant.
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	tenantID := ctx.Value(tenantKey{})
+
+	return tenantID, tenantID != nil
+}
+
+// EnableTenancy scopes the given tables by column: every SELECT, UPDATE and
+// DELETE gets "table.column = ?" (or "alias.column = ?") bound to the tenant
+// from the builder's context, for the builder's table and for tables it
+// inner-joins, and Insert fills the column in. Queries without a tenant fail
+// with ErrNoTenant unless the builder calls WithoutTenant. Outer joins of
+// scoped tables are refused, as a WHERE condition would turn them into inner
+// joins; scope those in the join condition and use WithoutTenant.
+func (d *DB) EnableTenancy(column string, tables ...string) *DB {
+	if d.tenantTables == nil {
+		d.tenantTables = make(map[string]string)
+	}
+	for _, table := range tables {
+		d.tenantTables[table] = column
+	}
+
+	return d
+}
+
+// WithoutTenant lets an (admin) builder run on a tenant-scoped table without
+// tenant filtering.
+func (qb *QueryBuilder) WithoutTenant() *QueryBuilder {
+	qb.tenantBypass = true
+
+	return qb
+}
+
+// tenant returns the tenant column and value for the builder's table, if
+// scoped.
+func (qb *QueryBuilder) tenant() (string, interface{}, bool, error) {
+	table, _ := parseTableRef(qb.table)
+	column, ok := qb.db.tenantTables[table]
+	if !ok || qb.tenantBypass {
+		return "", nil, false, nil
+	}
+
+	tenantID, ok := TenantFromContext(qb.context())
+	if !ok {
+		return "", nil, false, fmt.Errorf("%w: %s", ErrNoTenant, table)
+	}
+
+	return column, tenantID, true, nil
+}
+
+// tenantColumns returns the qualified tenant column of every scoped table in
+// the query, the builder's table first, then its joins.
+func (qb *QueryBuilder) tenantColumns() ([]string, error) {
+	var columns []string
+	scope := func(ref string) bool {
+		table, alias := parseTableRef(ref)
+		column, ok := qb.db.tenantTables[table]
+		if !ok {
+			return false
+		}
+		if alias == "" {
+			alias = table
+		}
+		columns = append(columns, alias+"."+column)
+		return true
+	}
+
+	scope(qb.table)
+	for _, join := range qb.joins {
+		if scope(joinRef(join)) && isOuterJoin(join) {
+			return nil, fmt.Errorf("tenant-scoped table in outer join on %s: %s", qb.table, join)
+		}
+	}
+
+	return columns, nil
+}
+
+// isOuterJoin reports whether the join clause is a LEFT, RIGHT or FULL join.
+func isOuterJoin(join string) bool {
+	joinType := strings.ToUpper(join[:strings.Index(join, "JOIN ")])
+
+	return strings.Contains(joinType, "LEFT") || strings.Contains(joinType, "RIGHT") ||
+		strings.Contains(joinType, "FULL") || strings.Contains(joinType, "OUTER")
+}
+
+// applyTenant adds the tenant conditions to the WHERE clause. Existing
+// conditions are grouped first so an OrWhere cannot escape the filter.
+func (qb *QueryBuilder) applyTenant() {
+	if qb.tenantBypass {
+		return
+	}
+	columns, err := qb.tenantColumns()
+	if err != nil {
+		qb.setError(err)
+		return
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	tenantID, ok := TenantFromContext(qb.context())
+	if !ok {
+		qb.setError(fmt.Errorf("%w: %s", ErrNoTenant, qb.table))
+		return
+	}
+
+	qb.groupWhere()
+	for _, column := range columns {
+		qb.Where(column, "=", tenantID)
+	}
+}
+
+// injectTenant sets the tenant column of inserted data and refuses writes
+// that would move a row to another tenant.
+func (qb *QueryBuilder) injectTenant(action string, data map[string]interface{}) error {
+	column, tenantID, ok, err := qb.tenant()
+	if err != nil || !ok {
+		return err
+	}
+
+	if value, exists := data[column]; exists && fmt.Sprint(value) != fmt.Sprint(tenantID) {
+		return fmt.Errorf("%s on %s: %s %v does not match the context tenant", action, qb.table, column, value)
+	}
+	if action == "insert" {
+		data[column] = tenantID
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTenantScoping(t *testing.T) {
+	ctx := WithTenant(context.Background(), 7)
+	tests := []struct {
+		name  string
+		build func(d *DB) *QueryBuilder
+		want  string
+	}{
+		{"plain table", func(d *DB) *QueryBuilder {
+			return d.Table("users").WithContext(ctx).Where("active", "=", 1)
+		}, "SELECT * FROM users WHERE active = ? AND users.tenant_id = ?"},
+		{"alias", func(d *DB) *QueryBuilder {
+			return d.Table("users u").WithContext(ctx)
+		}, "SELECT * FROM users u WHERE u.tenant_id = ?"},
+		{"AS alias", func(d *DB) *QueryBuilder {
+			return d.Table("users AS u").WithContext(ctx)
+		}, "SELECT * FROM users AS u WHERE u.tenant_id = ?"},
+		{"inner join", func(d *DB) *QueryBuilder {
+			return d.Table("users u").WithContext(ctx).InnerJoin("orders o", "o.user_id = u.id")
+		}, "SELECT * FROM users u INNER JOIN orders o ON o.user_id = u.id WHERE u.tenant_id = ? AND o.tenant_id = ?"},
+		{"unscoped join", func(d *DB) *QueryBuilder {
+			return d.Table("users").WithContext(ctx).LeftJoin("countries", "countries.id = users.country_id")
+		}, "SELECT * FROM users LEFT JOIN countries ON countries.id = users.country_id WHERE users.tenant_id = ?"},
+		{"scoped join only", func(d *DB) *QueryBuilder {
+			return d.Table("countries c").WithContext(ctx).InnerJoin("users u", "u.country_id = c.id")
+		}, "SELECT * FROM countries c INNER JOIN users u ON u.country_id = c.id WHERE u.tenant_id = ?"},
+	}
+	for _, tt := range tests {
+		d := testDB(t).EnableTenancy("tenant_id", "users", "orders")
+		query, params := tt.build(d).Build()
+		if query != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, query, tt.want)
+		}
+		for _, param := range params {
+			if !reflect.DeepEqual(param, 7) && !reflect.DeepEqual(param, 1) {
+				t.Errorf("%s: unexpected param %#v", tt.name, param)
+			}
+		}
+	}
+}
+
+func TestTenantScopingFailsClosed(t *testing.T) {
+	d := testDB(t).EnableTenancy("tenant_id", "users", "orders")
+	ctx := WithTenant(context.Background(), 7)
+
+	qb := d.Table("users u").WithContext(ctx).LeftJoin("orders o", "o.user_id = u.id")
+	if _, _, err := qb.ToSql(); err == nil {
+		t.Error("outer join of a scoped table: expected an error")
+	}
+
+	qb = d.Table("users u")
+	if _, _, err := qb.ToSql(); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("aliased table without tenant: got %v, want ErrNoTenant", err)
+	}
+
+	qb = d.Table("countries").InnerJoin("orders", "orders.country_id = countries.id")
+	if _, _, err := qb.ToSql(); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("joined table without tenant: got %v, want ErrNoTenant", err)
+	}
+
+	qb = d.Table("users u").WithContext(ctx).LeftJoin("orders o", "o.user_id = u.id AND o.tenant_id = 7").WithoutTenant()
+	if _, _, err := qb.ToSql(); err != nil {
+		t.Errorf("WithoutTenant: %v", err)
+	}
+}
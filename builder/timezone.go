@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timezone sets the builder's default time zone (an IANA name such as
+// "Asia/Dhaka", or "UTC"). SelectConvertTz converts to it when toTz is empty,
+// and time.Time parameters are bound as their wall clock time in it, as
+// strings, since the driver would convert a time.Time back to the DSN's loc.
+// Named zones in CONVERT_TZ need the MySQL time zone tables to be loaded.
+func (qb *QueryBuilder) Timezone(tz string) *QueryBuilder {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		qb.setError(fmt.Errorf("invalid timezone %s: %w", tz, err))
+		return qb
+	}
+
+	qb.timezone = tz
+	qb.location = loc
+
+	return qb
+}
+
+// SelectConvertTz selects CONVERT_TZ(column, fromTz, toTz) AS alias. An empty
+// fromTz means "+00:00"; an empty toTz falls back to the builder's Timezone.
+func (qb *QueryBuilder) SelectConvertTz(column, fromTz, toTz, alias string) *QueryBuilder {
+	if fromTz == "" {
+		fromTz = "+00:00"
+	}
+	if toTz == "" {
+		toTz = qb.timezone
+	}
+	if toTz == "" {
+		qb.setError(fmt.Errorf("SelectConvertTz on %s: no target timezone", column))
+		return qb
+	}
+
+	expr := fmt.Sprintf("CONVERT_TZ(%s, %s, %s)", column, quoteLiteral(fromTz), quoteLiteral(toTz))
+	if alias != "" {
+		expr += " AS " + alias
+	}
+
+	return qb.Select(expr)
+}
@@ -0,0 +1,53 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhereInTuples adds a row-value IN condition, for lookups on several
+// columns at once such as a composite key:
+//
+//	qb.WhereInTuples([]string{"order_id", "line"}, [][]interface{}{{7, 1}, {7, 2}})
+//	// WHERE (order_id, line) IN ((?, ?), (?, ?))
+//
+// No tuples matches nothing.
+func (qb *QueryBuilder) WhereInTuples(columns []string, tuples [][]interface{}) *QueryBuilder {
+	return qb.whereTuples("IN", columns, tuples)
+}
+
+// WhereNotInTuples is the NOT IN form of WhereInTuples. No tuples matches
+// every row.
+func (qb *QueryBuilder) WhereNotInTuples(columns []string, tuples [][]interface{}) *QueryBuilder {
+	return qb.whereTuples("NOT IN", columns, tuples)
+}
+
+func (qb *QueryBuilder) whereTuples(operator string, columns []string, tuples [][]interface{}) *QueryBuilder {
+	if len(columns) == 0 {
+		qb.setError(fmt.Errorf("WhereInTuples: no columns given"))
+		return qb
+	}
+	if len(tuples) == 0 {
+		if operator == "IN" {
+			qb.where = append(qb.where, "1 = 0")
+		}
+		return qb
+	}
+
+	row := "(" + placeholders(len(columns)) + ")"
+	rows := make([]string, len(tuples))
+	for i, tuple := range tuples {
+		if len(tuple) != len(columns) {
+			qb.setError(fmt.Errorf("WhereInTuples: tuple %d has %d values for %d columns", i, len(tuple), len(columns)))
+			return qb
+		}
+		for j, value := range tuple {
+			qb.bind(columns[j], value)
+		}
+		rows[i] = row
+	}
+
+	qb.where = append(qb.where, "("+strings.Join(columns, ", ")+") "+operator+" ("+strings.Join(rows, ", ")+")")
+
+	return qb
+}
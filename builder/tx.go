@@ -0,0 +1,207 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tx is a transaction started from a DB. Builders created with Tx.Table run
+// inside the transaction and keep the DB's settings.
+type Tx struct {
+	*sql.Tx
+	db *DB
+
+	// savepoints counts the nested Transaction calls, naming their savepoints.
+	savepoints int
+
+	watch *txState
+}
+
+// TxOption tunes a transaction started by Begin or Transaction.
+type TxOption func(opts *sql.TxOptions)
+
+// WithTxOptions applies opts as given to BeginTx.
+func WithTxOptions(opts sql.TxOptions) TxOption {
+	return func(o *sql.TxOptions) { *o = opts }
+}
+
+// ReadUncommitted runs the transaction at READ UNCOMMITTED.
+func ReadUncommitted() TxOption { return isolation(sql.LevelReadUncommitted) }
+
+// ReadCommitted runs the transaction at READ COMMITTED, so each statement
+// sees the data committed before it started.
+func ReadCommitted() TxOption { return isolation(sql.LevelReadCommitted) }
+
+// RepeatableRead runs the transaction at REPEATABLE READ, InnoDB's default.
+func RepeatableRead() TxOption { return isolation(sql.LevelRepeatableRead) }
+
+// Serializable runs the transaction at SERIALIZABLE.
+func Serializable() TxOption { return isolation(sql.LevelSerializable) }
+
+// ReadOnlyTx starts the transaction READ ONLY, which lets InnoDB skip
+// assigning it a transaction id and rejects writes inside it.
+func ReadOnlyTx() TxOption {
+	return func(o *sql.TxOptions) { o.ReadOnly = true }
+}
+
+func isolation(level sql.IsolationLevel) TxOption {
+	return func(o *sql.TxOptions) { o.Isolation = level }
+}
+
+// txOptions folds options into the *sql.TxOptions for BeginTx, or nil for
+// the server defaults.
+func txOptions(options []TxOption) *sql.TxOptions {
+	if len(options) == 0 {
+		return nil
+	}
+
+	opts := &sql.TxOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
+
+// Begin starts a transaction, by default with the session's isolation level:
+//
+//	tx, err := db.Begin(builder.ReadCommitted(), builder.ReadOnlyTx())
+func (d *DB) Begin(options ...TxOption) (*Tx, error) {
+	return d.BeginTx(context.Background(), txOptions(options))
+}
+
+// BeginTx starts a transaction with the given context and options.
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tx{Tx: tx, db: d}
+	if d.txWatch != nil {
+		d.txWatch.track(t)
+	}
+
+	return t, nil
+}
+
+// Transaction runs fn inside a transaction, committing when fn returns nil and
+// rolling back when it returns an error or panics. options are as for Begin.
+func (d *DB) Transaction(fn func(tx *Tx) error, options ...TxOption) (err error) {
+	return d.TransactionContext(context.Background(), fn, options...)
+}
+
+// TransactionContext is Transaction with a context, which starts the
+// transaction with BeginTx.
+func (d *DB) TransactionContext(ctx context.Context, fn func(tx *Tx) error, options ...TxOption) (err error) {
+	tx, err := d.BeginTx(ctx, txOptions(options))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Transaction runs fn as a nested unit of work behind a savepoint: when fn
+// returns an error or panics, only its changes are rolled back and the
+// outer transaction carries on. Code written against Transaction nests this
+// way inside a test transaction (see qbtest.WithRollback).
+func (tx *Tx) Transaction(fn func(tx *Tx) error) (err error) {
+	tx.savepoints++
+	savepoint := fmt.Sprintf("qb_sp_%d", tx.savepoints)
+	if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); rbErr != nil {
+			return fmt.Errorf("%w (rollback to savepoint failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	_, err = tx.Exec("RELEASE SAVEPOINT " + savepoint)
+
+	return err
+}
+
+// Table starts a new query against table inside the transaction.
+func (tx *Tx) Table(table string) *QueryBuilder {
+	return tx.db.Table(table).UseConnection(tx.Tx)
+}
+
+// WithTx sets the options of the transactions the builder starts on its own:
+// for audited writes, cascading deletes and, on the source builder,
+// RefreshSummaryTable.
+func (qb *QueryBuilder) WithTx(options ...TxOption) *QueryBuilder {
+	qb.txOptions = append(qb.txOptions, options...)
+
+	return qb
+}
+
+// txBeginner is implemented by runners that can start a transaction
+// (*sql.DB and *sql.Conn).
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// inTransaction runs fn with the builder pointed at a transaction. When the
+// builder already runs inside one (or on a runner that cannot begin one), fn
+// runs on the current runner.
+func (qb *QueryBuilder) inTransaction(fn func() error) error {
+	if qb.err != nil {
+		return qb.err
+	}
+
+	beginner, ok := qb.runner.(txBeginner)
+	if !ok {
+		return fn()
+	}
+
+	tx, err := beginner.BeginTx(qb.context(), txOptions(qb.txOptions))
+	if err != nil {
+		return err
+	}
+
+	runner := qb.runner
+	qb.runner = tx
+	committed := false
+	defer func() {
+		qb.runner = runner
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TxWarningKind tells why a transaction was reported.
+type TxWarningKind int
+
+const (
+	// TxLongRunning is reported once for a transaction still open after
+	// the threshold given to WatchTransactions.
+	TxLongRunning TxWarningKind = iota
+	// TxLeaked is reported for a Tx that became garbage without Commit or
+	// Rollback; it has been rolled back to free its connection.
+	TxLeaked
+)
+
+// TxInfo describes an open transaction.
+type TxInfo struct {
+	Started time.Time
+	Age     time.Duration
+	Caller  string // file:line of the Begin or Transaction call
+}
+
+// TxWarning is passed to the WatchTransactions callback.
+type TxWarning struct {
+	Kind TxWarningKind
+	TxInfo
+}
+
+type txWatch struct {
+	threshold time.Duration
+	fn        func(TxWarning)
+
+	mu     sync.Mutex
+	nextID uint64
+	open   map[uint64]*txState
+}
+
+// txState is the tracking state of one Tx. Timers and the open list hold it
+// rather than the Tx, so an abandoned Tx can still be collected and reported.
+type txState struct {
+	watch   *txWatch
+	id      uint64
+	started time.Time
+	caller  string
+	done    int32
+	timer   *time.Timer
+}
+
+// WatchTransactions tracks the transactions started with Begin, BeginTx and
+// Transaction, with the place they were started from, and calls fn when one
+// stays open longer than threshold or is dropped without Commit or Rollback.
+// Long transactions hold locks and undo history, and leaked ones each keep a
+// pool connection busy. fn runs on its own goroutine:
+//
+//	db.WatchTransactions(10*time.Second, func(w builder.TxWarning) {
+//		log.Printf("transaction from %s open for %s", w.Caller, w.Age)
+//	})
+func (d *DB) WatchTransactions(threshold time.Duration, fn func(w TxWarning)) *DB {
+	d.txWatch = &txWatch{threshold: threshold, fn: fn, open: make(map[uint64]*txState)}
+
+	return d
+}
+
+// OpenTransactions lists the transactions currently open, oldest first. It
+// needs WatchTransactions.
+func (d *DB) OpenTransactions() []TxInfo {
+	w := d.txWatch
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	infos := make([]TxInfo, 0, len(w.open))
+	for _, state := range w.open {
+		infos = append(infos, state.info())
+	}
+	w.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Started.Before(infos[j].Started) })
+
+	return infos
+}
+
+// track starts watching tx.
+func (w *txWatch) track(tx *Tx) {
+	w.mu.Lock()
+	w.nextID++
+	state := &txState{watch: w, id: w.nextID, started: time.Now(), caller: callerOutsidePackage()}
+	w.open[state.id] = state
+	w.mu.Unlock()
+
+	state.timer = time.AfterFunc(w.threshold, func() {
+		if atomic.LoadInt32(&state.done) == 0 {
+			w.fn(TxWarning{Kind: TxLongRunning, TxInfo: state.info()})
+		}
+	})
+	tx.watch = state
+
+	inner := tx.Tx
+	runtime.SetFinalizer(tx, func(*Tx) {
+		if w.finish(state) {
+			inner.Rollback()
+			go w.fn(TxWarning{Kind: TxLeaked, TxInfo: state.info()})
+		}
+	})
+}
+
+// finish stops watching a transaction, reporting whether it was still open.
+func (w *txWatch) finish(state *txState) bool {
+	if !atomic.CompareAndSwapInt32(&state.done, 0, 1) {
+		return false
+	}
+	state.timer.Stop()
+
+	w.mu.Lock()
+	delete(w.open, state.id)
+	w.mu.Unlock()
+
+	return true
+}
+
+func (s *txState) info() TxInfo {
+	return TxInfo{Started: s.started, Age: time.Since(s.started), Caller: s.caller}
+}
+
+// Commit commits the transaction.
+func (tx *Tx) Commit() error {
+	tx.finished()
+
+	return tx.Tx.Commit()
+}
+
+// Rollback aborts the transaction.
+func (tx *Tx) Rollback() error {
+	tx.finished()
+
+	return tx.Tx.Rollback()
+}
+
+func (tx *Tx) finished() {
+	if tx.watch != nil {
+		tx.watch.watch.finish(tx.watch)
+	}
+}
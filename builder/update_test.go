@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUpdateRequiresConditions(t *testing.T) {
+	d := testDB(t).EnableTenancy("tenant_id", "users")
+	ctx := WithTenant(context.Background(), 7)
+	data := map[string]interface{}{"active": 0}
+
+	if _, err := d.Table("posts").Update(data); !errors.Is(err, ErrNoConditions) {
+		t.Errorf("Update without Where: got %v, want ErrNoConditions", err)
+	}
+	if _, err := d.Table("users").WithContext(ctx).Update(data); !errors.Is(err, ErrNoConditions) {
+		t.Errorf("Update with only the tenant scope: got %v, want ErrNoConditions", err)
+	}
+
+	qb := d.Table("posts")
+	qb.Build()
+	if _, err := qb.Where("id", "=", 1).Update(data); errors.Is(err, ErrNoConditions) {
+		t.Error("Update with a Where added after Build: got ErrNoConditions")
+	}
+	if _, err := d.Table("posts").Where("id", "=", 1).Update(data); errors.Is(err, ErrNoConditions) {
+		t.Error("Update with Where: got ErrNoConditions")
+	}
+	if _, err := d.Table("posts").UpdateAll(data); errors.Is(err, ErrNoConditions) {
+		t.Error("UpdateAll: got ErrNoConditions")
+	}
+}
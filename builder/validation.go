@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validator checks data before it is written. action is "insert" or "update";
+// for updates data only holds the columns being changed.
+type Validator interface {
+	Validate(action string, data map[string]interface{}) error
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc func(action string, data map[string]interface{}) error
+
+func (f ValidatorFunc) Validate(action string, data map[string]interface{}) error {
+	return f(action, data)
+}
+
+// FieldError describes a rejected field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError collects field-level errors; validators can return it to
+// let callers report every problem at once.
+type ValidationError struct {
+	Table  string
+	Fields []FieldError
+}
+
+// Add records a field error.
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns e when it holds field errors, or nil.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.Field+": "+field.Message)
+	}
+
+	return fmt.Sprintf("validation failed for %s: %s", e.Table, strings.Join(parts, "; "))
+}
+
+// RegisterValidator runs v before every Insert, BulkInsert and Update on table.
+func (d *DB) RegisterValidator(table string, v Validator) *DB {
+	if d.validators == nil {
+		d.validators = make(map[string][]Validator)
+	}
+	d.validators[table] = append(d.validators[table], v)
+
+	return d
+}
+
+func (qb *QueryBuilder) validate(action string, data map[string]interface{}) error {
+	for _, v := range qb.db.validators[qb.table] {
+		if err := v.Validate(action, data); err != nil {
+			if verr, ok := err.(*ValidationError); ok && verr.Table == "" {
+				verr.Table = qb.table
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// prepareWrite validates data for action ("insert" or "update") and returns
+// a copy transformed for storage.
+func (qb *QueryBuilder) prepareWrite(action string, data map[string]interface{}) (map[string]interface{}, error) {
+	prepared := make(map[string]interface{}, len(data))
+	for column, value := range data {
+		prepared[column] = value
+	}
+
+	if err := qb.injectTenant(action, prepared); err != nil {
+		return nil, err
+	}
+	if err := qb.validate(action, prepared); err != nil {
+		return nil, err
+	}
+	if err := qb.validateEnums(prepared); err != nil {
+		return nil, err
+	}
+	if err := qb.encodeJSONValues(prepared); err != nil {
+		return nil, err
+	}
+	if err := qb.encryptValues(prepared); err != nil {
+		return nil, err
+	}
+
+	return prepared, nil
+}
+
+// scan reads rows and transforms the stored values for the caller.
+func (qb *QueryBuilder) scan(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, scanners, err := qb.columnScanners(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result, more, err := scanRows(rows, qb.effectiveRowLimit().max)
+	if err != nil {
+		return nil, err
+	}
+	if result, err = qb.checkRowLimit(result, more); err != nil {
+		return nil, err
+	}
+
+	if err := qb.decryptValues(result); err != nil {
+		return nil, err
+	}
+	scanned := make(map[string]bool)
+	if scanners != nil {
+		if err := applyScanners(result, columns, scanners); err != nil {
+			return nil, err
+		}
+		for i, fn := range scanners {
+			scanned[columns[i]] = fn != nil
+		}
+	}
+	if err := qb.decodeJSONValues(result, scanned); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// toSlice converts any slice or array (other than []byte) to []interface{}.
+func toSlice(value interface{}) ([]interface{}, bool) {
+	if values, ok := value.([]interface{}); ok {
+		return values, true
+	}
+	if _, ok := value.([]byte); ok {
+		return nil, false
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+
+	return values, true
+}
@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed server version.
+type Version struct {
+	Major, Minor, Patch int
+	MariaDB             bool
+	Raw                 string // as reported by VERSION()
+}
+
+// ParseVersion parses a VERSION() string such as "8.0.36-0ubuntu0.22.04.1"
+// or "10.11.6-MariaDB-1:10.11.6+maria~ubu2204".
+func ParseVersion(raw string) (Version, error) {
+	v := Version{Raw: raw, MariaDB: strings.Contains(strings.ToLower(raw), "mariadb")}
+
+	s := raw
+	// Old MariaDB releases prefix a fake 5.5.5- for client compatibility
+	if v.MariaDB && strings.HasPrefix(s, "5.5.5-") {
+		s = s[len("5.5.5-"):]
+	}
+	if i := strings.IndexAny(s, "-+~ "); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return Version{}, fmt.Errorf("unrecognised server version: %q", raw)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i := 0; i < len(parts) && i < len(numbers); i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("unrecognised server version: %q", raw)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// AtLeast reports whether v is major.minor.patch or newer.
+func (v Version) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+
+	return v.Patch >= patch
+}
+
+// String returns the flavour and number, e.g. "MySQL 5.7.44".
+func (v Version) String() string {
+	flavour := "MySQL"
+	if v.MariaDB {
+		flavour = "MariaDB"
+	}
+
+	return fmt.Sprintf("%s %d.%d.%d", flavour, v.Major, v.Minor, v.Patch)
+}
+
+// Feature is a server capability that not every supported version has.
+type Feature int
+
+// Features checked by Supports, Require and, internally, by the builder
+// methods that rely on them.
+const (
+	FeatureCTE Feature = iota
+	FeatureWindowFunctions
+	FeatureSkipLocked
+	FeatureNoWait
+	FeatureForShare
+	FeatureFunctionalIndex
+	FeatureReturning
+	FeatureSequences
+)
+
+// minVersion is the first MySQL and MariaDB release with a feature; a zero
+// major version means never.
+type minVersion struct {
+	name           string
+	mysql, mariadb [3]int
+}
+
+var featureVersions = map[Feature]minVersion{
+	FeatureCTE:             {"common table expressions", [3]int{8, 0, 1}, [3]int{10, 2, 1}},
+	FeatureWindowFunctions: {"window functions", [3]int{8, 0, 2}, [3]int{10, 2, 0}},
+	FeatureSkipLocked:      {"SKIP LOCKED", [3]int{8, 0, 1}, [3]int{10, 6, 0}},
+	FeatureNoWait:          {"NOWAIT", [3]int{8, 0, 1}, [3]int{10, 3, 0}},
+	FeatureForShare:        {"FOR SHARE", [3]int{8, 0, 1}, [3]int{}},
+	FeatureFunctionalIndex: {"functional indexes", [3]int{8, 0, 13}, [3]int{}},
+	FeatureReturning:       {"RETURNING", [3]int{}, [3]int{10, 5, 0}},
+	FeatureSequences:       {"sequences", [3]int{}, [3]int{10, 3, 0}},
+}
+
+// Supports reports whether the server version has feature.
+func (v Version) Supports(feature Feature) bool {
+	min, ok := featureVersions[feature]
+	if !ok {
+		return false
+	}
+	first := min.mysql
+	if v.MariaDB {
+		first = min.mariadb
+	}
+
+	return first[0] != 0 && v.AtLeast(first[0], first[1], first[2])
+}
+
+// ServerVersion returns the version of the server, queried once and cached.
+func (d *DB) ServerVersion() (Version, error) {
+	return d.ServerVersionContext(context.Background())
+}
+
+// ServerVersionContext is ServerVersion with a context.
+func (d *DB) ServerVersionContext(ctx context.Context) (Version, error) {
+	d.versionMu.Lock()
+	defer d.versionMu.Unlock()
+
+	if d.version != nil {
+		return *d.version, nil
+	}
+
+	raw, err := d.Table("").WithContext(ctx).GetVariable("version")
+	if err != nil {
+		return Version{}, fmt.Errorf("detect server version: %w", err)
+	}
+	v, err := ParseVersion(raw)
+	if err != nil {
+		return Version{}, err
+	}
+	d.version = &v
+
+	return v, nil
+}
+
+// Supports reports whether the server has feature.
+func (d *DB) Supports(feature Feature) (bool, error) {
+	v, err := d.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+
+	return v.Supports(feature), nil
+}
+
+// Require returns an ErrUnsupported error naming the feature and the server
+// version when the server lacks feature, e.g.
+// "builder: unsupported by the server: SKIP LOCKED on MySQL 5.7.44".
+func (d *DB) Require(feature Feature) error {
+	return d.require(context.Background(), feature)
+}
+
+func (d *DB) require(ctx context.Context, feature Feature) error {
+	v, err := d.ServerVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+	if v.Supports(feature) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s on %s", ErrUnsupported, featureVersions[feature].name, v)
+}
+
+// requireFeatures checks the features the built statement relies on, before
+// it reaches a server that would reject it with a syntax error. Other
+// dialects are left to the server.
+func (qb *QueryBuilder) requireFeatures() error {
+	if name := qb.db.Dialect().Name(); name != "mysql" && name != "mariadb" {
+		return nil
+	}
+
+	var features []Feature
+	switch qb.lockWait {
+	case "SKIP LOCKED":
+		features = append(features, FeatureSkipLocked)
+	case "NOWAIT":
+		features = append(features, FeatureNoWait)
+	}
+	if qb.lock == "FOR SHARE" {
+		features = append(features, FeatureForShare)
+	}
+
+	for _, feature := range features {
+		if err := qb.db.require(qb.context(), feature); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
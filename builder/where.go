@@ -0,0 +1,155 @@
+package builder
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WhereMap ANDs an equality condition per entry, e.g. filters decoded from
+// JSON. A nil value becomes IS NULL and a slice becomes IN (an empty slice
+// matches nothing). Keys are applied in sorted order so the SQL is stable.
+func (qb *QueryBuilder) WhereMap(conditions map[string]interface{}) *QueryBuilder {
+	columns := make([]string, 0, len(conditions))
+	for column := range conditions {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		qb.whereValue(column, conditions[column])
+	}
+
+	return qb
+}
+
+// whereValue adds an equality condition with nil and slice handling.
+func (qb *QueryBuilder) whereValue(column string, value interface{}) {
+	if isNull(value) {
+		qb.WhereNull(column)
+		return
+	}
+
+	if values, ok := toSlice(value); ok {
+		if len(values) == 0 {
+			qb.where = append(qb.where, "1 = 0")
+			return
+		}
+		qb.WhereIn(column, values)
+		return
+	}
+
+	qb.Where(column, "=", value)
+}
+
+// structOperators maps the operator part of a `qb:"column,op"` tag to SQL.
+var structOperators = map[string]string{
+	"eq":   "=",
+	"neq":  "!=",
+	"gt":   ">",
+	"gte":  ">=",
+	"lt":   "<",
+	"lte":  "<=",
+	"like": "LIKE",
+	"in":   "IN",
+	"nseq": "<=>",
+}
+
+// WhereStruct turns a tagged filter struct into WHERE conditions:
+//
+//	type UserFilter struct {
+//		Status string    `qb:"status,eq"`
+//		From   time.Time `qb:"created_at,gte"`
+//		IDs    []int     `qb:"id,in"`
+//		Admin  *bool     `qb:"is_admin"` // operator defaults to eq
+//		Team   *int      `qb:"team_id,nseq"` // NULL-safe <=>
+//	}
+//
+// Fields without a qb tag and fields holding their zero value are skipped;
+// use a pointer to filter on a zero value such as false. Embedded structs
+// are walked as well.
+func (qb *QueryBuilder) WhereStruct(filter interface{}) *QueryBuilder {
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return qb
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		qb.setError(fmt.Errorf("WhereStruct expects a struct, got %T", filter))
+		return qb
+	}
+
+	qb.whereStructFields(v)
+
+	return qb
+}
+
+func (qb *QueryBuilder) whereStructFields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		tag, tagged := field.Tag.Lookup("qb")
+		if !tagged {
+			if field.Anonymous && value.Kind() == reflect.Struct {
+				qb.whereStructFields(value)
+			}
+			continue
+		}
+		if tag == "-" || field.PkgPath != "" || value.IsZero() {
+			continue
+		}
+
+		column, op := tag, "eq"
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			column, op = tag[:comma], strings.TrimSpace(tag[comma+1:])
+		}
+		operator, ok := structOperators[op]
+		if !ok {
+			qb.setError(fmt.Errorf("field %s: unknown operator %q", field.Name, op))
+			return
+		}
+
+		for value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+
+		switch operator {
+		case "LIKE":
+			qb.WhereLike(column, fmt.Sprint(value.Interface()))
+		case "IN":
+			values, ok := toSlice(value.Interface())
+			if !ok {
+				qb.setError(fmt.Errorf("field %s: operator in needs a slice", field.Name))
+				return
+			}
+			if len(values) > 0 {
+				qb.WhereIn(column, values)
+			}
+		default:
+			qb.Where(column, operator, value.Interface())
+		}
+	}
+}
+
+// isNull reports whether value is a SQL NULL: nil, a nil pointer, or a
+// driver.Valuer such as sql.NullString whose value is nil.
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return true
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		return err == nil && v == nil
+	}
+
+	return false
+}
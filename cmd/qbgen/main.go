@@ -0,0 +1,64 @@
+// Command qbgen writes a package of column constants for each table given,
+// see package gen:
+//
+//	qbgen -dsn 'user:pass@tcp(localhost:3306)/app' -out internal/tables users orders
+//
+// creates internal/tables/users/users.go and internal/tables/orders/orders.go.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/ruhulfbr/go-mysql-qb/gen"
+)
+
+func main() {
+	dsn := flag.String("dsn", os.Getenv("QBGEN_DSN"), "MySQL DSN of the schema (default $QBGEN_DSN)")
+	out := flag.String("out", ".", "directory to create the table packages in")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: qbgen -dsn DSN [-out DIR] TABLE...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *dsn == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := sql.Open("mysql", *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	for _, table := range flag.Args() {
+		columns, err := gen.Columns(ctx, conn, table)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pkg := gen.PackageName(table)
+		src, err := gen.Generate(table, pkg, columns)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		dir := filepath.Join(*out, pkg)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, pkg+".go"), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
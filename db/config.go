@@ -0,0 +1,205 @@
+package db
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Config describes how to reach a MySQL server.
+type Config struct {
+	Username string
+	Password string
+	Host     string
+	DBName   string
+
+	// Port is joined to Host when Host does not already carry one.
+	Port int
+
+	// Protocol is "tcp" (the default) or "unix". For "unix", Socket holds the
+	// socket path.
+	Protocol string
+	Socket   string
+
+	// Params are passed through to the DSN unchanged.
+	Params map[string]string
+
+	// Charset and Collation set the connection character set, e.g. "utf8mb4"
+	// and "utf8mb4_unicode_ci" for emoji-safe text.
+	Charset   string
+	Collation string
+
+	// ParseTime scans DATE and DATETIME columns into time.Time, interpreted
+	// in Loc (UTC when nil).
+	ParseTime bool
+	Loc       *time.Location
+
+	// TLS enables encrypted connections; nil leaves TLS off.
+	TLS *TLSConfig
+
+	// PingTimeout bounds the initial ping; zero means no extra timeout.
+	PingTimeout time.Duration
+
+	// StrictMode is what Open does when the server's sql_mode is not strict.
+	StrictMode StrictModeCheck
+}
+
+// StrictModeCheck selects how Open reacts to a non-strict sql_mode, under
+// which MySQL silently truncates data that does not fit a column.
+type StrictModeCheck int
+
+const (
+	// StrictModeIgnore skips the check.
+	StrictModeIgnore StrictModeCheck = iota
+
+	// StrictModeWarn logs a warning.
+	StrictModeWarn
+
+	// StrictModeRequire fails the connection.
+	StrictModeRequire
+)
+
+// TLSConfig describes the TLS settings registered with the mysql driver.
+type TLSConfig struct {
+	// Name is the key the tls.Config is registered under. When empty a key
+	// derived from the other fields is used.
+	Name string
+
+	SkipVerify bool
+	ServerName string
+
+	// CAFile is a PEM bundle used to verify the server certificate.
+	CAFile string
+
+	// CertFile and KeyFile hold a PEM client certificate and key.
+	CertFile string
+	KeyFile  string
+}
+
+// DSN builds the driver DSN for the config, registering its TLS settings
+// with the driver first when present.
+func (c Config) DSN() (string, error) {
+	mc := mysql.NewConfig()
+	mc.User = c.Username
+	mc.Passwd = c.Password
+	mc.DBName = c.DBName
+
+	addr, err := c.address()
+	if err != nil {
+		return "", err
+	}
+	mc.Net = c.protocol()
+	mc.Addr = addr
+
+	if len(c.Params) > 0 {
+		mc.Params = make(map[string]string, len(c.Params))
+		for key, value := range c.Params {
+			mc.Params[key] = value
+		}
+	}
+
+	if c.Charset != "" {
+		if mc.Params == nil {
+			mc.Params = make(map[string]string)
+		}
+		mc.Params["charset"] = c.Charset
+	}
+	mc.Collation = c.Collation
+	mc.ParseTime = c.ParseTime
+	if c.Loc != nil {
+		mc.Loc = c.Loc
+	}
+
+	if c.TLS != nil {
+		name, err := c.TLS.register()
+		if err != nil {
+			return "", err
+		}
+		mc.TLSConfig = name
+	}
+
+	return mc.FormatDSN(), nil
+}
+
+func (c Config) protocol() string {
+	if c.Protocol == "" {
+		return "tcp"
+	}
+
+	return c.Protocol
+}
+
+func (c Config) address() (string, error) {
+	switch c.protocol() {
+	case "unix":
+		if c.Socket != "" {
+			return c.Socket, nil
+		}
+		return c.Host, nil
+	case "tcp", "tcp4", "tcp6":
+		if c.Port == 0 {
+			return c.Host, nil
+		}
+		if _, _, err := net.SplitHostPort(c.Host); err == nil {
+			return c.Host, nil
+		}
+		return net.JoinHostPort(c.Host, strconv.Itoa(c.Port)), nil
+	default:
+		return "", fmt.Errorf("unsupported protocol: %s", c.Protocol)
+	}
+}
+
+func (t *TLSConfig) register() (string, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: t.SkipVerify,
+		ServerName:         t.ServerName,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return "", fmt.Errorf("error reading TLS CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("no certificates found in TLS CA file %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return "", fmt.Errorf("error loading TLS client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	name := t.Name
+	if name == "" {
+		name = t.derivedName()
+	}
+
+	if err := mysql.RegisterTLSConfig(name, config); err != nil {
+		return "", fmt.Errorf("error registering TLS config: %w", err)
+	}
+
+	return name, nil
+}
+
+// derivedName keeps registration idempotent: the same settings always map to
+// the same registry key.
+func (t *TLSConfig) derivedName() string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%t|%s|%s|%s|%s", t.SkipVerify, t.ServerName, t.CAFile, t.CertFile, t.KeyFile)))
+
+	return "qb-" + hex.EncodeToString(sum[:8])
+}
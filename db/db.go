@@ -1,17 +1,17 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 )
 
 func Connect(username, password, host, dbname string) *sql.DB {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, host, dbname)
-
 	var DBConnection *sql.DB
 	var err error
-	DBConnection, err = sql.Open("mysql", dsn)
+	DBConnection, err = sql.Open("mysql", buildDSN(username, password, host, dbname))
 	if err != nil {
 		log.Fatalf("Error creating the database DBConnection: %v", err)
 	}
@@ -23,6 +23,49 @@ func Connect(username, password, host, dbname string) *sql.DB {
 	return DBConnection
 }
 
+// ConnectContext opens a connection and verifies it with a ping bounded by ctx
+// and pingTimeout (zero means no extra timeout). Unlike Connect, failures are
+// returned to the caller instead of terminating the process.
+func ConnectContext(ctx context.Context, username, password, host, dbname string, pingTimeout time.Duration) (*sql.DB, error) {
+	return ConnectConfig(ctx, Config{
+		Username:    username,
+		Password:    password,
+		Host:        host,
+		DBName:      dbname,
+		PingTimeout: pingTimeout,
+	})
+}
+
+// ConnectConfig opens a connection described by cfg and verifies it with a ping.
+func ConnectConfig(ctx context.Context, cfg Config) (*sql.DB, error) {
+	dsn, err := cfg.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	DBConnection, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the database connection: %w", err)
+	}
+
+	if cfg.PingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.PingTimeout)
+		defer cancel()
+	}
+
+	if err := DBConnection.PingContext(ctx); err != nil {
+		DBConnection.Close()
+		return nil, fmt.Errorf("database ping failed: %w", err)
+	}
+
+	return DBConnection, nil
+}
+
+func buildDSN(username, password, host, dbname string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, host, dbname)
+}
+
 func Close(DBConnection *sql.DB) {
 	if DBConnection != nil {
 		err := DBConnection.Close()
@@ -0,0 +1,53 @@
+package gen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestPackageName(t *testing.T) {
+	tests := map[string]string{
+		"users":       "users",
+		"order_items": "orderitems",
+		"2fa_codes":   "facodes",
+		"select":      "selecttable",
+		"Type":        "typetable",
+		"range":       "rangetable",
+	}
+	for table, want := range tests {
+		if got := PackageName(table); got != want {
+			t.Errorf("PackageName(%q) = %q, want %q", table, got, want)
+		}
+	}
+}
+
+func TestGenerateQuotesNames(t *testing.T) {
+	table := "odd\"table\nconst X = 1 //"
+	src, err := Generate(table, PackageName(table), []string{"id", `we"ird\col`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "odd.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+	if obj := file.Scope.Lookup("X"); obj != nil {
+		t.Errorf("table name injected a declaration:\n%s", src)
+	}
+	if !strings.Contains(string(src), `const Table = "odd\"table\nconst X = 1 //"`) {
+		t.Errorf("table constant not quoted:\n%s", src)
+	}
+	if !strings.Contains(string(src), `"we\"ird\\col"`) {
+		t.Errorf("column constant not quoted:\n%s", src)
+	}
+}
+
+func TestGenerateRejectsInvalidPackage(t *testing.T) {
+	for _, pkg := range []string{"func", "my-pkg", ""} {
+		if _, err := Generate("users", pkg, []string{"id"}); err == nil {
+			t.Errorf("Generate with package %q: expected an error", pkg)
+		}
+	}
+}
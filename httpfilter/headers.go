@@ -0,0 +1,44 @@
+package httpfilter
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// WritePageHeaders sets X-Total-Count and a Link header (RFC 8288) with the
+// first, prev, next and last pages of p on w. The links are r's URL with the
+// page and per_page parameters replaced, so the filters and sort carry over:
+//
+//	page, err := qb.Paginate(n, perPage)
+//	...
+//	httpfilter.WritePageHeaders(w, r, page)
+//
+// Call it before writing the body.
+func WritePageHeaders(w http.ResponseWriter, r *http.Request, p *builder.Page) {
+	h := w.Header()
+	h.Set("X-Total-Count", strconv.Itoa(p.Total))
+
+	links := []string{pageLink(r.URL, 1, p.PerPage, "first")}
+	if p.HasPrev() {
+		links = append(links, pageLink(r.URL, p.Page-1, p.PerPage, "prev"))
+	}
+	if p.HasNext() {
+		links = append(links, pageLink(r.URL, p.Page+1, p.PerPage, "next"))
+	}
+	links = append(links, pageLink(r.URL, p.LastPage, p.PerPage, "last"))
+	h.Set("Link", strings.Join(links, ", "))
+}
+
+func pageLink(u *url.URL, page, perPage int, rel string) string {
+	link := *u
+	values := link.Query()
+	values.Set("page", strconv.Itoa(page))
+	values.Set("per_page", strconv.Itoa(perPage))
+	link.RawQuery = values.Encode()
+
+	return "<" + link.String() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,203 @@
+// Package httpfilter turns REST-style query strings such as
+//
+//	?filter[status]=active&filter[age][gte]=18&sort=-created_at&page=2&per_page=20
+//
+// into query builder calls, accepting only whitelisted columns and operators.
+package httpfilter
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// Operators maps the operator names accepted in filter[column][op] to SQL.
+// "in" takes a comma-separated list.
+var Operators = map[string]string{
+	"eq":   "=",
+	"neq":  "!=",
+	"gt":   ">",
+	"gte":  ">=",
+	"lt":   "<",
+	"lte":  "<=",
+	"like": "LIKE",
+	"in":   "IN",
+}
+
+// Options configures Apply.
+type Options struct {
+	// Filterable maps filter names to columns; names not listed are rejected.
+	Filterable map[string]string
+
+	// Sortable maps sort names to columns; names not listed are rejected.
+	Sortable map[string]string
+
+	// AllowedOperators restricts the operator names; nil allows all Operators.
+	AllowedOperators []string
+
+	// DefaultPerPage is used when per_page is absent (default 20); MaxPerPage
+	// caps it (default 100).
+	DefaultPerPage int
+	MaxPerPage     int
+
+	// MaxPage is the highest page accepted (default 10000); deeper pages are
+	// rejected rather than sent as an ever larger OFFSET.
+	MaxPage int
+}
+
+// Apply adds the filters, sort order and page described by values to qb.
+func Apply(qb *builder.QueryBuilder, values url.Values, opts Options) error {
+	// Sorted keys keep the generated SQL stable between requests
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		vals := values[key]
+		if !strings.HasPrefix(key, "filter[") || len(vals) == 0 {
+			continue
+		}
+
+		name, op, err := parseFilterKey(key)
+		if err != nil {
+			return err
+		}
+		if err := applyFilter(qb, name, op, vals[len(vals)-1], opts); err != nil {
+			return err
+		}
+	}
+
+	if order := values.Get("sort"); order != "" {
+		if err := applySort(qb, order, opts); err != nil {
+			return err
+		}
+	}
+
+	return applyPage(qb, values, opts)
+}
+
+// parseFilterKey splits "filter[name]" or "filter[name][op]".
+func parseFilterKey(key string) (string, string, error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	end := strings.Index(rest, "]")
+	if end <= 0 {
+		return "", "", fmt.Errorf("malformed filter parameter: %s", key)
+	}
+
+	name, rest := rest[:end], rest[end+1:]
+	if rest == "" {
+		return name, "eq", nil
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") || len(rest) < 3 {
+		return "", "", fmt.Errorf("malformed filter parameter: %s", key)
+	}
+
+	return name, strings.ToLower(rest[1 : len(rest)-1]), nil
+}
+
+func applyFilter(qb *builder.QueryBuilder, name, op, value string, opts Options) error {
+	column, ok := opts.Filterable[name]
+	if !ok {
+		return fmt.Errorf("filtering on %s is not allowed", name)
+	}
+
+	operator, ok := Operators[op]
+	if !ok || !operatorAllowed(op, opts) {
+		return fmt.Errorf("filter operator %s is not allowed", op)
+	}
+
+	switch operator {
+	case "LIKE":
+		qb.WhereLike(column, value)
+	case "IN":
+		parts := strings.Split(value, ",")
+		items := make([]interface{}, len(parts))
+		for i, part := range parts {
+			items[i] = strings.TrimSpace(part)
+		}
+		qb.WhereIn(column, items)
+	default:
+		qb.Where(column, operator, value)
+	}
+
+	return nil
+}
+
+func operatorAllowed(op string, opts Options) bool {
+	if opts.AllowedOperators == nil {
+		return true
+	}
+	for _, allowed := range opts.AllowedOperators {
+		if allowed == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applySort handles "sort=-created_at,name"; a leading "-" sorts descending.
+func applySort(qb *builder.QueryBuilder, order string, opts Options) error {
+	terms, err := builder.ParseSort(order, opts.Sortable)
+	if err != nil {
+		return err
+	}
+
+	qb.OrderBy(terms)
+
+	return nil
+}
+
+func applyPage(qb *builder.QueryBuilder, values url.Values, opts Options) error {
+	perPage := opts.DefaultPerPage
+	if perPage <= 0 {
+		perPage = 20
+	}
+	maxPerPage := opts.MaxPerPage
+	if maxPerPage <= 0 {
+		maxPerPage = 100
+	}
+
+	if raw := values.Get("per_page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid per_page: %s", raw)
+		}
+		perPage = n
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	maxPage := opts.MaxPage
+	if maxPage <= 0 {
+		maxPage = 10000
+	}
+	// the offset must fit an int on 32-bit platforms too
+	if limit := math.MaxInt32/perPage + 1; maxPage > limit {
+		maxPage = limit
+	}
+
+	page := 1
+	if raw := values.Get("page"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid page: %s", raw)
+		}
+		if n > maxPage {
+			return fmt.Errorf("page %d is beyond the last allowed page %d", n, maxPage)
+		}
+		page = n
+	}
+
+	qb.Limit(perPage).Offset((page - 1) * perPage)
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package httpfilter
+
+import (
+	"database/sql"
+	"math"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+var testOptions = Options{
+	Filterable: map[string]string{"status": "users.status", "age": "users.age", "role": "users.role", "name": "users.name"},
+	Sortable:   map[string]string{"created": "users.created_at", "name": "users.name"},
+}
+
+// testTable returns a builder on a pool that is never connected to.
+func testTable(t *testing.T) *builder.QueryBuilder {
+	t.Helper()
+
+	conn, err := sql.Open("mysql", "test@tcp(127.0.0.1:1)/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return builder.NewDB(conn).Table("users")
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		opts   func(*Options)
+		sql    string
+		params []interface{}
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			sql:   "SELECT * FROM users LIMIT 20 OFFSET 0",
+		},
+		{
+			name:   "equality filter",
+			query:  "filter[status]=active",
+			sql:    "SELECT * FROM users WHERE users.status = ? LIMIT 20 OFFSET 0",
+			params: []interface{}{"active"},
+		},
+		{
+			name:   "operators in key order",
+			query:  "filter[name][like]=ann&filter[age][gte]=18",
+			sql:    "SELECT * FROM users WHERE users.age >= ? AND users.name LIKE ? LIMIT 20 OFFSET 0",
+			params: []interface{}{"18", "ann"},
+		},
+		{
+			name:   "in list",
+			query:  "filter[role][in]=admin, editor",
+			sql:    "SELECT * FROM users WHERE users.role IN (?, ?) LIMIT 20 OFFSET 0",
+			params: []interface{}{"admin", "editor"},
+		},
+		{
+			name:  "sort",
+			query: "sort=-created,name",
+			sql:   "SELECT * FROM users ORDER BY users.created_at DESC, users.name ASC LIMIT 20 OFFSET 0",
+		},
+		{
+			name:  "page",
+			query: "page=3&per_page=10",
+			sql:   "SELECT * FROM users LIMIT 10 OFFSET 20",
+		},
+		{
+			name:  "per_page capped",
+			query: "per_page=500",
+			sql:   "SELECT * FROM users LIMIT 100 OFFSET 0",
+		},
+		{
+			name:  "custom page sizes",
+			query: "page=2",
+			opts:  func(o *Options) { o.DefaultPerPage = 5; o.MaxPerPage = 5 },
+			sql:   "SELECT * FROM users LIMIT 5 OFFSET 5",
+		},
+		{
+			name:  "last allowed page",
+			query: "page=10000&per_page=100",
+			sql:   "SELECT * FROM users LIMIT 100 OFFSET 999900",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := testOptions
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+
+			qb := testTable(t)
+			if err := Apply(qb, values, opts); err != nil {
+				t.Fatal(err)
+			}
+			query, params := qb.Build()
+			if query != tt.sql {
+				t.Errorf("SQL = %s\nwant  %s", query, tt.sql)
+			}
+			if len(params) != 0 || len(tt.params) != 0 {
+				if !reflect.DeepEqual(params, tt.params) {
+					t.Errorf("params = %v, want %v", params, tt.params)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  func(*Options)
+		err   string
+	}{
+		{"unknown filter", "filter[password]=x", nil, "filtering on password is not allowed"},
+		{"unknown operator", "filter[age][between]=1", nil, "filter operator between is not allowed"},
+		{"disallowed operator", "filter[name][like]=a", func(o *Options) { o.AllowedOperators = []string{"eq"} }, "filter operator like is not allowed"},
+		{"malformed filter", "filter[age=1", nil, "malformed filter parameter"},
+		{"malformed operator", "filter[age]x=1", nil, "malformed filter parameter"},
+		{"unknown sort", "sort=password", nil, "password"},
+		{"zero page", "page=0", nil, "invalid page: 0"},
+		{"non-numeric page", "page=two", nil, "invalid page: two"},
+		{"negative per_page", "per_page=-5", nil, "invalid per_page: -5"},
+		{"page beyond default limit", "page=10001", nil, "page 10001 is beyond the last allowed page 10000"},
+		{"page beyond custom limit", "page=6", func(o *Options) { o.MaxPage = 5 }, "page 6 is beyond the last allowed page 5"},
+		{"page overflowing the offset", "page=2147483647&per_page=100", func(o *Options) { o.MaxPage = math.MaxInt32 }, "is beyond the last allowed page 21474837"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := testOptions
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+
+			err = Apply(testTable(t), values, opts)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -1,18 +1,99 @@
 package DB
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/ruhulfbr/go-mysql-qb/builder"
 	"github.com/ruhulfbr/go-mysql-qb/db"
+	"log"
+	"time"
 )
 
 var Connection *sql.DB
 
+// DB is a query builder handle on a connection pool.
+type DB = builder.DB
+
+// Tx is a transaction started from a DB.
+type Tx = builder.Tx
+
+// Config and TLSConfig describe a connection for ConnectWithConfig.
+type Config = db.Config
+type TLSConfig = db.TLSConfig
+
+// StrictModeCheck values for Config.StrictMode.
+const (
+	StrictModeIgnore  = db.StrictModeIgnore
+	StrictModeWarn    = db.StrictModeWarn
+	StrictModeRequire = db.StrictModeRequire
+)
+
 func ConnectDB(username, password, host, dbname string) {
 	Connection = db.Connect(username, password, host, dbname)
 }
 
+// ConnectMySQLContext connects like ConnectDB but returns an error instead of
+// exiting, and gives up once ctx is cancelled or pingTimeout elapses.
+func ConnectMySQLContext(ctx context.Context, username, password, host, dbname string, pingTimeout time.Duration) error {
+	conn, err := db.ConnectContext(ctx, username, password, host, dbname, pingTimeout)
+	if err != nil {
+		return err
+	}
+
+	Connection = conn
+
+	return nil
+}
+
+// ConnectWithConfig connects using a full connection Config, e.g. one with TLS enabled.
+func ConnectWithConfig(ctx context.Context, cfg Config) error {
+	conn, err := db.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	Connection = conn
+
+	return nil
+}
+
+// Open connects using cfg and returns a DB for it, with the MySQL or MariaDB
+// dialect selected from the server version and sql_mode checked as
+// cfg.StrictMode asks. Unlike ConnectWithConfig it does not touch the
+// package-level Connection; the caller closes the pool.
+func Open(ctx context.Context, cfg Config) (*DB, error) {
+	conn, err := db.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d := builder.NewDB(conn)
+	if err := d.DetectDialect(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if cfg.StrictMode != db.StrictModeIgnore {
+		if err := d.RequireStrictModeContext(ctx); err != nil {
+			if cfg.StrictMode == db.StrictModeRequire || !errors.Is(err, builder.ErrNotStrict) {
+				conn.Close()
+				return nil, err
+			}
+			log.Printf("warning: %v", err)
+		}
+	}
+
+	return d, nil
+}
+
+// NewFromDB wraps a connection pool managed by the application. The package
+// never closes it; CloseDB only affects the connection opened by ConnectDB.
+func NewFromDB(conn *sql.DB) *DB {
+	return builder.NewDB(conn)
+}
+
 func CloseDB() {
 	db.Close(Connection)
 }
@@ -0,0 +1,243 @@
+// Package outbox implements the transactional outbox pattern on the query
+// builder: events are written in the same transaction as the business rows
+// they describe, and a Poller later dispatches them to a broker. An event is
+// dispatched at least once; consumers should be idempotent.
+//
+// An outbox table looks like:
+//
+//	CREATE TABLE outbox (
+//		id            BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+//		topic         VARCHAR(255) NOT NULL,
+//		payload       JSON NOT NULL,
+//		attempts      INT UNSIGNED NOT NULL DEFAULT 0,
+//		available_at  DATETIME(6) NOT NULL,
+//		dispatched_at DATETIME(6) NULL,
+//		created_at    DATETIME(6) NOT NULL,
+//		KEY idx_outbox_pending (dispatched_at, available_at)
+//	);
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// Event is an outbox row claimed for dispatch.
+type Event struct {
+	ID       int64
+	Topic    string
+	Payload  json.RawMessage
+	Attempts int // including the current one
+}
+
+// Decode unmarshals the event payload into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Outbox writes and dispatches events kept in one table.
+type Outbox struct {
+	db    *builder.DB
+	table string
+}
+
+// New returns an Outbox on the given table.
+func New(d *builder.DB, table string) *Outbox {
+	return &Outbox{db: d, table: table}
+}
+
+// Write records an event inside tx, so it is committed or rolled back
+// together with the business writes:
+//
+//	err := db.Transaction(func(tx *builder.Tx) error {
+//		if _, err := tx.Table("orders").Insert(order); err != nil {
+//			return err
+//		}
+//		return events.Write(tx, "order.created", order)
+//	})
+func (o *Outbox) Write(tx *builder.Tx, topic string, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("outbox: encode payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	_, err = tx.Table(o.table).Insert(map[string]interface{}{
+		"topic":        topic,
+		"payload":      string(encoded),
+		"attempts":     0,
+		"available_at": now,
+		"created_at":   now,
+	})
+
+	return err
+}
+
+// Poller claims pending events and hands them to Dispatch. A claimed event is
+// hidden from other pollers for Visibility; it is marked dispatched when
+// Dispatch returns nil and retried after Visibility otherwise.
+type Poller struct {
+	outbox *Outbox
+
+	// Dispatch delivers one event, typically publishing it to a broker.
+	Dispatch func(ctx context.Context, e Event) error
+
+	// Batch is the number of events claimed per poll (default 100).
+	Batch int
+
+	// Interval is the pause after a poll that found nothing (default 1s).
+	Interval time.Duration
+
+	// Visibility is how long a claimed event stays hidden (default 1m).
+	Visibility time.Duration
+
+	// OnError, when set, is told about failed polls and dispatches; Run keeps
+	// going either way.
+	OnError func(err error)
+}
+
+// Poller returns a Poller dispatching the outbox events with dispatch.
+func (o *Outbox) Poller(dispatch func(ctx context.Context, e Event) error) *Poller {
+	return &Poller{
+		outbox:     o,
+		Dispatch:   dispatch,
+		Batch:      100,
+		Interval:   time.Second,
+		Visibility: time.Minute,
+	}
+}
+
+// Run polls until ctx is cancelled and returns ctx.Err().
+func (p *Poller) Run(ctx context.Context) error {
+	for {
+		n, err := p.Poll(ctx)
+		if err != nil {
+			p.report(err)
+		}
+
+		if n == 0 || err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(p.Interval):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll claims one batch of events, dispatches them in order and returns how
+// many were claimed. A dispatch failure is reported to OnError and ends the
+// batch, so no event is delivered ahead of an earlier one: the failed event
+// and the rest of the batch become available again after Visibility, and are
+// retried in order on a later poll.
+func (p *Poller) Poll(ctx context.Context) (int, error) {
+	events, err := p.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		if err := p.Dispatch(ctx, e); err != nil {
+			p.report(fmt.Errorf("outbox: dispatch event %d (%s): %w", e.ID, e.Topic, err))
+			break
+		}
+		_, err := p.outbox.db.Table(p.outbox.table).WithContext(ctx).
+			Where("id", "=", e.ID).
+			Update(map[string]interface{}{"dispatched_at": time.Now().UTC()})
+		if err != nil {
+			p.report(fmt.Errorf("outbox: mark event %d dispatched: %w", e.ID, err))
+		}
+	}
+
+	return len(events), nil
+}
+
+func (p *Poller) report(err error) {
+	if p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// claim locks a batch of pending events with SKIP LOCKED and pushes their
+// availability back by Visibility, in one transaction.
+func (p *Poller) claim(ctx context.Context) ([]Event, error) {
+	table := p.outbox.table
+	if !utils.IsValidIdentifier(table) {
+		return nil, fmt.Errorf("outbox: invalid table name: %q", table)
+	}
+
+	var events []Event
+	err := p.outbox.db.TransactionContext(ctx, func(tx *builder.Tx) error {
+		now := time.Now().UTC()
+		rows, err := tx.Table(table).WithContext(ctx).
+			Select("id", "topic", "payload", "attempts").
+			WhereNull("dispatched_at").
+			Where("available_at", "<=", now).
+			OrderBy("id").
+			Limit(p.Batch).
+			ForUpdate().
+			SkipLocked().
+			Get()
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		// the rows are locked, so each can be set to its next attempt count;
+		// events with the same count are claimed in one update
+		byAttempts := make(map[int][]interface{})
+		for _, row := range rows {
+			e, err := eventFromRow(row)
+			if err != nil {
+				return err
+			}
+			events = append(events, e)
+			byAttempts[e.Attempts] = append(byAttempts[e.Attempts], e.ID)
+		}
+
+		for attempts, ids := range byAttempts {
+			_, err := tx.Table(table).WithContext(ctx).WhereIn("id", ids).Update(map[string]interface{}{
+				"attempts":     attempts,
+				"available_at": now.Add(p.Visibility),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func eventFromRow(row map[string]interface{}) (Event, error) {
+	id, err := utils.ToInt64(row["id"])
+	if err != nil {
+		return Event{}, fmt.Errorf("outbox: event id: %w", err)
+	}
+	attempts, err := utils.ToInt64(row["attempts"])
+	if err != nil {
+		return Event{}, fmt.Errorf("outbox: event %d attempts: %w", id, err)
+	}
+
+	e := Event{ID: id, Attempts: int(attempts) + 1}
+	e.Topic, _ = row["topic"].(string)
+	switch payload := row["payload"].(type) {
+	case string:
+		e.Payload = json.RawMessage(payload)
+	case []byte:
+		e.Payload = json.RawMessage(payload)
+	}
+
+	return e, nil
+}
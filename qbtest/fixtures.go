@@ -0,0 +1,297 @@
+package qbtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// LoadFixtures replaces the contents of the tables described by the fixture
+// files in dir. Each file holds the rows of the table it is named after
+// (users.json, orders.yml, ...), as a JSON array of objects or a YAML list of
+// flat mappings:
+//
+//	# users.yml
+//	- id: 1
+//	  name: Alice
+//	  email: "alice@example.com"
+//
+// Tables are emptied children first and filled parents first, following
+// their foreign keys, and rows are inserted with BulkInsert. Tenant scoping
+// and global filters are bypassed. Only the YAML subset above (scalars,
+// quoted strings, null, comments) is understood, to keep the package free of
+// dependencies.
+func LoadFixtures(d *builder.DB, dir string) error {
+	fixtures, err := readFixtures(dir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return nil
+	}
+
+	tables := make([]string, 0, len(fixtures))
+	for table := range fixtures {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	parents, referenced, err := foreignKeys(d, tables)
+	if err != nil {
+		return err
+	}
+	order, err := parentsFirst(tables, parents)
+	if err != nil {
+		return err
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		table := order[i]
+		// TRUNCATE is refused on tables referenced by a foreign key
+		statement := "TRUNCATE TABLE `" + table + "`"
+		if referenced[table] {
+			statement = "DELETE FROM `" + table + "`"
+		}
+		if _, err := d.Conn().Exec(statement); err != nil {
+			return fmt.Errorf("qbtest: clearing %s: %w", table, err)
+		}
+	}
+
+	for _, table := range order {
+		rows := fixtures[table]
+		// BulkInsert takes its columns from the first row, so insert runs of
+		// rows that share the same columns together
+		for start := 0; start < len(rows); {
+			end := start + 1
+			for end < len(rows) && sameColumns(rows[start], rows[end]) {
+				end++
+			}
+			_, err := d.Table(table).WithoutTenant().WithoutGlobalFilters().BulkInsert(rows[start:end])
+			if err != nil {
+				return fmt.Errorf("qbtest: loading %s: %w", table, err)
+			}
+			start = end
+		}
+	}
+
+	return nil
+}
+
+func readFixtures(dir string) (map[string][]map[string]interface{}, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("qbtest: reading fixtures: %w", err)
+	}
+
+	fixtures := make(map[string][]map[string]interface{})
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+		table := strings.TrimSuffix(entry.Name(), ext)
+		if !utils.IsValidIdentifier(table) {
+			return nil, fmt.Errorf("qbtest: fixture %s: invalid table name: %q", entry.Name(), table)
+		}
+		if _, ok := fixtures[table]; ok {
+			return nil, fmt.Errorf("qbtest: more than one fixture file for table %s", table)
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("qbtest: reading fixture: %w", err)
+		}
+
+		var rows []map[string]interface{}
+		if ext == ".json" {
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.UseNumber()
+			err = decoder.Decode(&rows)
+		} else {
+			rows, err = parseYAMLRows(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("qbtest: parsing %s: %w", path, err)
+		}
+		fixtures[table] = rows
+	}
+
+	return fixtures, nil
+}
+
+// foreignKeys returns, for each fixture table, the other fixture tables it
+// references, and which tables are referenced by any foreign key at all.
+func foreignKeys(d *builder.DB, tables []string) (map[string][]string, map[string]bool, error) {
+	rows, err := d.Conn().Query("SELECT TABLE_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE" +
+		" WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL")
+	if err != nil {
+		return nil, nil, fmt.Errorf("qbtest: reading foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	loaded := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		loaded[table] = true
+	}
+
+	parents := make(map[string][]string)
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var child, parent string
+		if err := rows.Scan(&child, &parent); err != nil {
+			return nil, nil, err
+		}
+		referenced[parent] = true
+		if loaded[child] && loaded[parent] && child != parent {
+			parents[child] = append(parents[child], parent)
+		}
+	}
+
+	return parents, referenced, rows.Err()
+}
+
+// parentsFirst orders tables so every table comes after the tables it
+// references.
+func parentsFirst(tables []string, parents map[string][]string) ([]string, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(tables))
+	order := make([]string, 0, len(tables))
+
+	var visit func(table string) error
+	visit = func(table string) error {
+		switch state[table] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("qbtest: foreign key cycle through %s", table)
+		}
+		state[table] = visiting
+		for _, parent := range parents[table] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[table] = done
+		order = append(order, table)
+
+		return nil
+	}
+
+	for _, table := range tables {
+		if err := visit(table); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func sameColumns(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for column := range a {
+		if _, ok := b[column]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseYAMLRows parses a YAML list of flat mappings with scalar values.
+func parseYAMLRows(content []byte) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+	var row map[string]interface{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "- ") || line == "-":
+			row = make(map[string]interface{})
+			rows = append(rows, row)
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "-"), " ")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		case strings.HasPrefix(line, " ") && row != nil:
+		default:
+			return nil, fmt.Errorf("line %d: expected a list item", n)
+		}
+
+		key, value, ok := splitYAMLPair(strings.TrimSpace(line))
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", n)
+		}
+		parsed, err := yamlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		row[key] = parsed
+	}
+
+	return rows, scanner.Err()
+}
+
+func splitYAMLPair(line string) (key, value string, ok bool) {
+	i := strings.Index(line, ":")
+	if i <= 0 || (i+1 < len(line) && line[i+1] != ' ') {
+		return "", "", false
+	}
+	key = strings.Trim(strings.TrimSpace(line[:i]), `"'`)
+
+	return key, strings.TrimSpace(line[i+1:]), true
+}
+
+func yamlScalar(value string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("unterminated string %s", value)
+		}
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1), nil
+	}
+
+	// Unquoted scalars may carry a trailing comment
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	switch value {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+
+	return value, nil
+}
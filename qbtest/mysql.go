@@ -0,0 +1,210 @@
+package qbtest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// Environment variables read by StartMySQL.
+const (
+	// DSNEnv points StartMySQL at an existing server instead of starting a
+	// container, e.g. a CI service container.
+	DSNEnv = "QBTEST_MYSQL_DSN"
+
+	// ImageEnv overrides the MySQL image (mysql:8.0 by default).
+	ImageEnv = "QBTEST_MYSQL_IMAGE"
+)
+
+// ServerTB is the subset of testing.TB used by StartMySQL.
+type ServerTB interface {
+	TB
+	Skipf(format string, args ...interface{})
+	Cleanup(fn func())
+}
+
+// MySQLOptions configures StartMySQLWith.
+type MySQLOptions struct {
+	// Image is the container image; ImageEnv or mysql:8.0 when empty.
+	Image string
+
+	// Migrations is a directory of .sql files applied in file name order
+	// once the server is up. A file may hold several statements.
+	Migrations string
+
+	// StartTimeout bounds the wait for the server to accept connections
+	// (default 2 minutes).
+	StartTimeout time.Duration
+}
+
+// StartMySQL returns a DB connected to a disposable MySQL server, for
+// integration tests. See StartMySQLWith.
+func StartMySQL(t ServerTB) *builder.DB {
+	t.Helper()
+
+	return StartMySQLWith(t, MySQLOptions{})
+}
+
+// StartMySQLWith starts a throwaway MySQL container with the docker CLI,
+// waits for it to accept connections, applies the migrations and returns a
+// connected DB. The container is removed when the test ends. When DSNEnv is
+// set that server is used instead, and when neither it nor docker is
+// available the test is skipped.
+func StartMySQLWith(t ServerTB, opts MySQLOptions) *builder.DB {
+	t.Helper()
+
+	if opts.StartTimeout <= 0 {
+		opts.StartTimeout = 2 * time.Minute
+	}
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		dsn = startContainer(t, opts)
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("qbtest: parsing DSN: %v", err)
+	}
+	// tests get the statement handling of production connections
+	cfg.MultiStatements = false
+
+	conn, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatalf("qbtest: opening connection: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := waitForServer(conn, opts.StartTimeout); err != nil {
+		t.Fatalf("qbtest: MySQL did not become ready: %v", err)
+	}
+
+	if opts.Migrations != "" {
+		if err := migrateWith(cfg, opts.Migrations); err != nil {
+			t.Fatalf("qbtest: %v", err)
+		}
+	}
+
+	return builder.NewDB(conn)
+}
+
+// migrateWith applies the migrations on a connection of its own that allows
+// several statements per file.
+func migrateWith(cfg *mysql.Config, dir string) error {
+	migrations := cfg.Clone()
+	migrations.MultiStatements = true
+
+	conn, err := sql.Open("mysql", migrations.FormatDSN())
+	if err != nil {
+		return fmt.Errorf("opening migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	return migrate(conn, dir)
+}
+
+// startContainer runs the MySQL container and returns its DSN.
+func startContainer(t ServerTB, opts MySQLOptions) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skipf("qbtest: docker not available and %s not set", DSNEnv)
+	}
+
+	image := opts.Image
+	if image == "" {
+		image = os.Getenv(ImageEnv)
+	}
+	if image == "" {
+		image = "mysql:8.0"
+	}
+
+	id, err := docker("run", "-d", "--rm",
+		"-e", "MYSQL_ROOT_PASSWORD=qbtest",
+		"-e", "MYSQL_DATABASE=qbtest",
+		"-p", "127.0.0.1::3306",
+		image)
+	if err != nil {
+		t.Fatalf("qbtest: starting MySQL container: %v", err)
+	}
+	t.Cleanup(func() { docker("rm", "-f", id) })
+
+	port, err := docker("port", id, "3306/tcp")
+	if err != nil {
+		t.Fatalf("qbtest: reading container port: %v", err)
+	}
+	// docker port may list an IPv6 binding too; the first line is enough
+	address := strings.SplitN(port, "\n", 2)[0]
+
+	return fmt.Sprintf("root:qbtest@tcp(%s)/qbtest", address)
+}
+
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// waitForServer pings conn until it answers or timeout passes. A fresh
+// container restarts mysqld once during initialisation, so early successes
+// are not trusted on their own; the ping is retried until it answers twice
+// in a row.
+func waitForServer(conn *sql.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	streak := 0
+	var err error
+	for time.Now().Before(deadline) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err = conn.PingContext(ctx)
+		cancel()
+		if err == nil {
+			if streak++; streak == 2 {
+				return nil
+			}
+		} else {
+			streak = 0
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return err
+}
+
+func migrate(conn *sql.DB, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("listing migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading migration: %w", err)
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			continue
+		}
+		if _, err := conn.Exec(string(content)); err != nil {
+			return fmt.Errorf("applying migration %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package qbtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// PlanOptions configures the plan assertions.
+type PlanOptions struct {
+	// AllowFullScan lists tables small enough that scanning them is fine
+	// (lookup tables, settings, ...).
+	AllowFullScan []string
+}
+
+var (
+	plansMu sync.Mutex
+	plans   = map[string]func() *builder.QueryBuilder{}
+)
+
+// RegisterPlan records a query whose plan AssertPlans checks. build must
+// return a fresh builder each time; register the application's hot queries
+// from an init function or TestMain.
+func RegisterPlan(name string, build func() *builder.QueryBuilder) {
+	plansMu.Lock()
+	defer plansMu.Unlock()
+
+	plans[name] = build
+}
+
+// AssertPlans runs EXPLAIN for every registered query and fails the test for
+// each one that scans a whole table or reads a table without using an index.
+// Run it against a database holding the real schema (e.g. from StartMySQL).
+func AssertPlans(t TB, opts PlanOptions) {
+	t.Helper()
+
+	plansMu.Lock()
+	names := make([]string, 0, len(plans))
+	for name := range plans {
+		names = append(names, name)
+	}
+	builds := make(map[string]func() *builder.QueryBuilder, len(plans))
+	for name, build := range plans {
+		builds[name] = build
+	}
+	plansMu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		assertPlan(t, name, builds[name](), opts)
+	}
+}
+
+// AssertPlan runs EXPLAIN for qb and fails the test when the plan scans a
+// whole table or reads a table without using an index.
+func AssertPlan(t TB, qb *builder.QueryBuilder, opts PlanOptions) {
+	t.Helper()
+
+	assertPlan(t, "query", qb, opts)
+}
+
+func assertPlan(t TB, name string, qb *builder.QueryBuilder, opts PlanOptions) {
+	t.Helper()
+
+	plan, err := qb.Explain()
+	if err != nil {
+		t.Errorf("%s: EXPLAIN failed: %v", name, err)
+		return
+	}
+
+	allowed := make(map[string]bool, len(opts.AllowFullScan))
+	for _, table := range opts.AllowFullScan {
+		allowed[table] = true
+	}
+
+	for _, row := range plan {
+		table := planValue(row["table"])
+		// Derived tables, unions and constant rows have no index to use
+		if table == "" || strings.HasPrefix(table, "<") || allowed[table] {
+			continue
+		}
+
+		access := planValue(row["type"])
+		switch {
+		case access == "ALL":
+			t.Errorf("%s: full table scan on %s (rows: %s, possible keys: %s)\n%s",
+				name, table, planValue(row["rows"]), planValue(row["possible_keys"]), qb.ToSQL())
+		case planValue(row["key"]) == "" && access != "system" && access != "const" && access != "":
+			t.Errorf("%s: no index used on %s (access type %s)\n%s", name, table, access, qb.ToSQL())
+		}
+	}
+}
+
+func planValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,81 @@
+// Package qbtest provides helpers for testing code built on the query builder.
+package qbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite
+// golden files instead of comparing against them.
+const UpdateEnv = "QBTEST_UPDATE"
+
+// NormalizeSQL collapses runs of whitespace so formatting differences do not
+// fail comparisons.
+func NormalizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// AssertSQL fails the test when the SQL built by qb differs from wantSQL
+// (ignoring whitespace) or its parameters differ from wantParams.
+func AssertSQL(t TB, qb *builder.QueryBuilder, wantSQL string, wantParams ...interface{}) {
+	t.Helper()
+
+	query, params := qb.Build()
+	if got, want := NormalizeSQL(query), NormalizeSQL(wantSQL); got != want {
+		t.Errorf("SQL mismatch\n got: %s\nwant: %s", got, want)
+	}
+
+	if len(params) == 0 && len(wantParams) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params mismatch\n got: %#v\nwant: %#v", params, wantParams)
+	}
+}
+
+// AssertGolden compares the SQL and parameters built by qb with the golden
+// file testdata/<name>.golden. Run the tests with QBTEST_UPDATE=1 to create
+// or refresh the file.
+func AssertGolden(t TB, qb *builder.QueryBuilder, name string) {
+	t.Helper()
+
+	query, params := qb.Build()
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("encoding params: %v", err)
+	}
+	got := fmt.Sprintf("%s\n-- params: %s\n", NormalizeSQL(query), encoded)
+
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with %s=1 to create it): %v", UpdateEnv, err)
+	}
+	if got != string(want) {
+		t.Errorf("golden mismatch for %s\n got: %s\nwant: %s", path, got, want)
+	}
+}
+
+// TB is the subset of testing.TB used by the helpers.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
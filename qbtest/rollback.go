@@ -0,0 +1,27 @@
+package qbtest
+
+import "github.com/ruhulfbr/go-mysql-qb/builder"
+
+// WithRollback runs fn inside a transaction on d that is rolled back when fn
+// returns, so tests can share a database without cleaning up after
+// themselves:
+//
+//	qbtest.WithRollback(t, db, func(tx *builder.Tx) {
+//		tx.Table("users").Insert(map[string]interface{}{"name": "alice"})
+//		// ... assertions; the row is gone afterwards
+//	})
+//
+// Run the code under test through tx; use tx.Transaction where it needs its
+// own unit of work, which nests as a savepoint. DDL and TRUNCATE commit
+// implicitly in MySQL and escape the rollback.
+func WithRollback(t TB, d *builder.DB, fn func(tx *builder.Tx)) {
+	t.Helper()
+
+	tx, err := d.Begin()
+	if err != nil {
+		t.Fatalf("qbtest: beginning transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	fn(tx)
+}
@@ -0,0 +1,197 @@
+// Package queue is a small database-backed job queue built on the query
+// builder. Workers claim jobs with SELECT ... FOR UPDATE SKIP LOCKED, so any
+// number of them can poll the same table without handing out a job twice.
+//
+// A queue table looks like:
+//
+//	CREATE TABLE jobs (
+//		id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+//		payload      JSON NOT NULL,
+//		attempts     INT UNSIGNED NOT NULL DEFAULT 0,
+//		available_at DATETIME(6) NOT NULL,
+//		created_at   DATETIME(6) NOT NULL,
+//		KEY idx_jobs_available (available_at)
+//	);
+//
+// A claimed job is hidden for the queue's Visibility and becomes available
+// again unless it is completed in time, so jobs of crashed workers are retried.
+// SKIP LOCKED needs MySQL 8.0 or MariaDB 10.6.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+	"github.com/ruhulfbr/go-mysql-qb/utils"
+)
+
+// DefaultVisibility is how long a claimed job stays hidden from other workers.
+const DefaultVisibility = 5 * time.Minute
+
+// Job is a claimed job.
+type Job struct {
+	ID       int64
+	Payload  json.RawMessage
+	Attempts int // including the current one
+}
+
+// Decode unmarshals the job payload into v.
+func (j Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Queue enqueues and claims jobs on a DB.
+type Queue struct {
+	db *builder.DB
+
+	// Visibility is how long a claimed job stays hidden before it is handed
+	// out again; DefaultVisibility unless changed.
+	Visibility time.Duration
+}
+
+// New returns a Queue on d.
+func New(d *builder.DB) *Queue {
+	return &Queue{db: d, Visibility: DefaultVisibility}
+}
+
+// Enqueue adds a job whose payload is the JSON encoding of payload, and
+// returns its ID.
+func (q *Queue) Enqueue(table string, payload interface{}) (int64, error) {
+	return q.EnqueueAfter(table, payload, 0)
+}
+
+// EnqueueAfter adds a job that becomes available after delay.
+func (q *Queue) EnqueueAfter(table string, payload interface{}, delay time.Duration) (int64, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("queue: encode payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	result, err := q.db.Table(table).Insert(map[string]interface{}{
+		"payload":      string(encoded),
+		"attempts":     0,
+		"available_at": now.Add(delay),
+		"created_at":   now,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// Dequeue claims up to batch available jobs, oldest first. It returns no
+// jobs, and no error, when the queue is empty or every available job is
+// claimed by another worker.
+func (q *Queue) Dequeue(table string, batch int) ([]Job, error) {
+	return q.DequeueContext(context.Background(), table, batch)
+}
+
+// DequeueContext is Dequeue with a context, which the claiming transaction
+// and its statements run under.
+func (q *Queue) DequeueContext(ctx context.Context, table string, batch int) ([]Job, error) {
+	if !utils.IsValidIdentifier(table) {
+		return nil, fmt.Errorf("queue: invalid table name: %q", table)
+	}
+	if batch <= 0 {
+		return nil, fmt.Errorf("queue: invalid batch size: %d", batch)
+	}
+
+	var jobs []Job
+	err := q.db.TransactionContext(ctx, func(tx *builder.Tx) error {
+		now := time.Now().UTC()
+		rows, err := tx.Table(table).WithContext(ctx).
+			Select("id", "payload", "attempts").
+			Where("available_at", "<=", now).
+			OrderBy("id").
+			Limit(batch).
+			ForUpdate().
+			SkipLocked().
+			Get()
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		// the rows are locked, so each can be set to its next attempt count;
+		// jobs with the same count are claimed in one update
+		byAttempts := make(map[int][]interface{})
+		for _, row := range rows {
+			job, err := jobFromRow(row)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			byAttempts[job.Attempts] = append(byAttempts[job.Attempts], job.ID)
+		}
+
+		for attempts, ids := range byAttempts {
+			_, err := tx.Table(table).WithContext(ctx).WhereIn("id", ids).Update(map[string]interface{}{
+				"attempts":     attempts,
+				"available_at": now.Add(q.Visibility),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Complete removes finished jobs from the queue.
+func (q *Queue) Complete(table string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	_, err := q.db.Table(table).WhereIn("id", values).Delete()
+
+	return err
+}
+
+// Release hands a claimed job back to the queue, available again after
+// delay, e.g. to retry a failed job with backoff.
+func (q *Queue) Release(table string, id int64, delay time.Duration) error {
+	_, err := q.db.Table(table).Where("id", "=", id).Update(map[string]interface{}{
+		"available_at": time.Now().UTC().Add(delay),
+	})
+
+	return err
+}
+
+func jobFromRow(row map[string]interface{}) (Job, error) {
+	id, err := utils.ToInt64(row["id"])
+	if err != nil {
+		return Job{}, fmt.Errorf("queue: job id: %w", err)
+	}
+	attempts, err := utils.ToInt64(row["attempts"])
+	if err != nil {
+		return Job{}, fmt.Errorf("queue: job %d attempts: %w", id, err)
+	}
+
+	var payload json.RawMessage
+	switch p := row["payload"].(type) {
+	case string:
+		payload = json.RawMessage(p)
+	case []byte:
+		payload = json.RawMessage(p)
+	}
+
+	return Job{ID: id, Payload: payload, Attempts: int(attempts) + 1}, nil
+}
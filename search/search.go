@@ -0,0 +1,347 @@
+// Package search compiles a small filter language, as typed into admin
+// search boxes, into query builder conditions:
+//
+//	status:active AND created_at>=2024-01-01 OR name~"foo%"
+//
+// A term is field, operator and value:
+//
+//	:   equals ("field:null" matches NULL)
+//	!:  differs ("field!:null" matches non-NULL)
+//	> >= < <=  compares
+//	~   LIKE
+//	!~  NOT LIKE
+//
+// Values run to the next space or parenthesis unless double-quoted, with \"
+// and \\ as escapes. Terms combine with AND (also implied between adjacent
+// terms), OR and NOT, case-insensitively, and parentheses; AND binds tighter
+// than OR. Only whitelisted fields are accepted and every value is bound as a
+// parameter.
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+// DefaultMaxTerms is used when Options.MaxTerms is zero.
+const DefaultMaxTerms = 32
+
+// DefaultMaxDepth is used when Options.MaxDepth is zero.
+const DefaultMaxDepth = 8
+
+// Options configures Compile and Apply.
+type Options struct {
+	// Fields maps the field names accepted in expressions to columns; names
+	// not listed are rejected.
+	Fields map[string]string
+
+	// MaxTerms limits the number of terms in one expression.
+	MaxTerms int
+
+	// MaxDepth limits how deeply parentheses and NOT nest.
+	MaxDepth int
+}
+
+// SyntaxError reports where an expression could not be parsed, for
+// displaying next to the search box.
+type SyntaxError struct {
+	Pos int // byte offset in the input
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("search: %s at position %d", e.Msg, e.Pos)
+}
+
+// Apply adds the conditions of the expression to qb. An empty expression
+// adds nothing.
+func Apply(qb *builder.QueryBuilder, input string, opts Options) error {
+	f, err := Compile(input, opts)
+	if err != nil {
+		return err
+	}
+	qb.Apply(f)
+
+	return nil
+}
+
+// Compile parses the expression into a Fragment, for reuse across builders.
+func Compile(input string, opts Options) (*builder.Fragment, error) {
+	if opts.MaxTerms == 0 {
+		opts.MaxTerms = DefaultMaxTerms
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+
+	p := &parser{input: input, opts: opts}
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return builder.NewFragment(func(*builder.QueryBuilder) {}), nil
+	}
+
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return f, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+	opts  Options
+	terms int
+	depth int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses terms joined by OR.
+func (p *parser) parseOr() (*builder.Fragment, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := []*builder.Fragment{f}
+	for p.keyword("OR") {
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, f)
+	}
+	if len(alternatives) == 1 {
+		return f, nil
+	}
+
+	return builder.AnyOf(alternatives...), nil
+}
+
+// parseAnd parses terms joined by AND or written next to each other.
+func (p *parser) parseAnd() (*builder.Fragment, error) {
+	var all []*builder.Fragment
+	for {
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, f)
+
+		if p.keyword("AND") {
+			continue
+		}
+		p.skipSpace()
+		if p.pos == len(p.input) || p.input[p.pos] == ')' || p.peekKeyword("OR") {
+			break
+		}
+	}
+	if len(all) == 1 {
+		return all[0], nil
+	}
+
+	return builder.NewFragment(func(qb *builder.QueryBuilder) { qb.Apply(all...) }), nil
+}
+
+func (p *parser) parseUnary() (*builder.Fragment, error) {
+	if p.keyword("NOT") {
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		defer p.unnest()
+
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return builder.Not(f), nil
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		defer p.unnest()
+
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos == len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return f, nil
+	}
+
+	return p.parseTerm()
+}
+
+// nest enters a parenthesis or NOT, failing beyond MaxDepth.
+func (p *parser) nest() error {
+	p.depth++
+	if p.depth > p.opts.MaxDepth {
+		return p.errorf("nested more than %d deep", p.opts.MaxDepth)
+	}
+
+	return nil
+}
+
+func (p *parser) unnest() {
+	p.depth--
+}
+
+// operators lists the term operators, longest first.
+var operators = []string{"!:", "!~", ">=", "<=", ":", "~", ">", "<"}
+
+func (p *parser) parseTerm() (*builder.Fragment, error) {
+	start := p.pos
+	name := p.word()
+	if name == "" {
+		if p.pos == len(p.input) {
+			return nil, p.errorf("expected a term")
+		}
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+	column, ok := p.opts.Fields[name]
+	if !ok {
+		return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("unknown field %q", name)}
+	}
+
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(p.input[p.pos:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected an operator after %s", name)
+	}
+	p.pos += len(op)
+
+	value, quoted, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	p.terms++
+	if p.terms > p.opts.MaxTerms {
+		return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("more than %d terms", p.opts.MaxTerms)}
+	}
+
+	var bound interface{} = value
+	if !quoted && strings.EqualFold(value, "null") {
+		if op != ":" && op != "!:" {
+			return nil, &SyntaxError{Pos: start, Msg: "null only works with : and !:"}
+		}
+		bound = nil
+	}
+
+	return builder.NewFragment(func(qb *builder.QueryBuilder) {
+		switch op {
+		case ":":
+			qb.Where(column, "=", bound)
+		case "!:":
+			qb.Where(column, "!=", bound)
+		case "~":
+			qb.WhereLike(column, value)
+		case "!~":
+			qb.WhereNotLike(column, value)
+		default:
+			qb.Where(column, op, value)
+		}
+	}), nil
+}
+
+// value reads a bare or double-quoted value.
+func (p *parser) value() (string, bool, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		start := p.pos
+		p.pos++
+		var b strings.Builder
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			switch {
+			case c == '"':
+				p.pos++
+				return b.String(), true, nil
+			case c == '\\' && p.pos+1 < len(p.input):
+				p.pos++
+				b.WriteByte(p.input[p.pos])
+			default:
+				b.WriteByte(c)
+			}
+			p.pos++
+		}
+		return "", false, &SyntaxError{Pos: start, Msg: "unterminated string"}
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !isSpace(p.input[p.pos]) && p.input[p.pos] != '(' && p.input[p.pos] != ')' {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", false, p.errorf("expected a value")
+	}
+
+	return p.input[start:p.pos], false, nil
+}
+
+// word reads a field name.
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.input) && isWordChar(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+// keyword consumes the keyword when it comes next.
+func (p *parser) keyword(kw string) bool {
+	if !p.peekKeyword(kw) {
+		return false
+	}
+	p.pos += len(kw)
+
+	return true
+}
+
+// peekKeyword reports whether the keyword, as a whole word, comes next. It
+// skips leading space.
+func (p *parser) peekKeyword(kw string) bool {
+	p.skipSpace()
+	end := p.pos + len(kw)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], kw) {
+		return false
+	}
+
+	return end == len(p.input) || isSpace(p.input[end]) || p.input[end] == '('
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
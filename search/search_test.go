@@ -0,0 +1,135 @@
+package search
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/ruhulfbr/go-mysql-qb/builder"
+)
+
+var fields = map[string]string{
+	"status":     "status",
+	"name":       "name",
+	"age":        "age",
+	"created_at": "users.created_at",
+}
+
+// testTable returns a builder on a pool that is never connected to.
+func testTable(t *testing.T) *builder.QueryBuilder {
+	t.Helper()
+
+	conn, err := sql.Open("mysql", "test@tcp(127.0.0.1:1)/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return builder.NewDB(conn).Table("users")
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		input  string
+		where  string
+		params []interface{}
+	}{
+		{"", "", nil},
+		{"status:active", "status = ?", []interface{}{"active"}},
+		{"status:active age>=18", "(status = ? AND age >= ?)", []interface{}{"active", "18"}},
+		{"status:active and age>=18", "(status = ? AND age >= ?)", []interface{}{"active", "18"}},
+		{"created_at<2024-01-01", "users.created_at < ?", []interface{}{"2024-01-01"}},
+		// AND binds tighter than OR
+		{`status:active OR name~"foo%" age>3`, "(status = ? OR (name LIKE ? AND age > ?))", []interface{}{"active", "foo%", "3"}},
+		{`(status:active OR status:trial) age<=3`, "((status = ? OR status = ?) AND age <= ?)", []interface{}{"active", "trial", "3"}},
+		{"NOT (status:a OR status:b)", "NOT ((status = ? OR status = ?))", []interface{}{"a", "b"}},
+		{"not status:a", "NOT (status = ?)", []interface{}{"a"}},
+		{"name!~bot%", "name NOT LIKE ?", []interface{}{"bot%"}},
+		{`name:"Jane Doe"`, "name = ?", []interface{}{"Jane Doe"}},
+		{`name:"a \"b\" \\c"`, "name = ?", []interface{}{`a "b" \c`}},
+		{`name:"OR"`, "name = ?", []interface{}{"OR"}},
+		{"status:null", "status IS NULL", nil},
+		{"status!:NULL", "status IS NOT NULL", nil},
+		{`name:"null"`, "name = ?", []interface{}{"null"}},
+	}
+	for _, tt := range tests {
+		qb := testTable(t)
+		if err := Apply(qb, tt.input, Options{Fields: fields}); err != nil {
+			t.Errorf("Apply(%q): %v", tt.input, err)
+			continue
+		}
+		query, params := qb.Build()
+
+		want := "SELECT * FROM users"
+		if tt.where != "" {
+			want += " WHERE " + tt.where
+		}
+		if query != want {
+			t.Errorf("Apply(%q): got %q, want %q", tt.input, query, want)
+		}
+		if len(params) != 0 || len(tt.params) != 0 {
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("Apply(%q): params %#v, want %#v", tt.input, params, tt.params)
+			}
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  Options
+		pos   int
+		msg   string
+	}{
+		{"password:x", Options{}, 0, `unknown field "password"`},
+		{"status:a secret:b", Options{}, 9, `unknown field "secret"`},
+		{"status", Options{}, 6, "expected an operator after status"},
+		{"status:", Options{}, 7, "expected a value"},
+		{`name:"open`, Options{}, 5, "unterminated string"},
+		{"(status:a", Options{}, 9, "missing )"},
+		{"status:a)", Options{}, 8, "unexpected ')'"},
+		{"status:a OR", Options{}, 11, "expected a term"},
+		{"age>null", Options{}, 0, "null only works with : and !:"},
+		{"status:a status:b status:c", Options{MaxTerms: 2}, 18, "more than 2 terms"},
+		{"((status:a))", Options{MaxDepth: 1}, 1, "nested more than 1 deep"},
+		{"NOT NOT status:a", Options{MaxDepth: 1}, 7, "nested more than 1 deep"},
+	}
+	for _, tt := range tests {
+		tt.opts.Fields = fields
+		_, err := Compile(tt.input, tt.opts)
+
+		var serr *SyntaxError
+		if !errors.As(err, &serr) {
+			t.Errorf("Compile(%q): got %v, want a SyntaxError", tt.input, err)
+			continue
+		}
+		if serr.Pos != tt.pos || serr.Msg != tt.msg {
+			t.Errorf("Compile(%q): got %q at %d, want %q at %d", tt.input, serr.Msg, serr.Pos, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestCompileDefaultLimits(t *testing.T) {
+	terms := strings.TrimSpace(strings.Repeat("status:a ", DefaultMaxTerms))
+	if _, err := Compile(terms, Options{Fields: fields}); err != nil {
+		t.Errorf("%d terms: %v", DefaultMaxTerms, err)
+	}
+	if _, err := Compile(terms+" status:a", Options{Fields: fields}); err == nil {
+		t.Errorf("%d terms: expected an error", DefaultMaxTerms+1)
+	}
+
+	nested := strings.Repeat("(", DefaultMaxDepth) + "status:a" + strings.Repeat(")", DefaultMaxDepth)
+	if _, err := Compile(nested, Options{Fields: fields}); err != nil {
+		t.Errorf("nesting %d deep: %v", DefaultMaxDepth, err)
+	}
+	if _, err := Compile("("+nested+")", Options{Fields: fields}); err == nil {
+		t.Errorf("nesting %d deep: expected an error", DefaultMaxDepth+1)
+	}
+	if _, err := Compile(strings.Repeat("NOT ", 100000)+"status:a", Options{Fields: fields}); err == nil {
+		t.Error("deep NOT chain: expected an error")
+	}
+}
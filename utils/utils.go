@@ -1,14 +1,20 @@
 package utils
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
 
 var AllowedOperators = map[string]bool{
-	"=":  true,
-	"!=": true,
-	"<":  true,
-	"<=": true,
-	">":  true,
-	">=": true,
+	"=":   true,
+	"!=":  true,
+	"<":   true,
+	"<=":  true,
+	">":   true,
+	">=":  true,
+	"<=>": true,
 }
 
 // IsValidOperator Exported function
@@ -20,3 +26,43 @@ func IsValidOperator(operator string) bool {
 
 	return true
 }
+
+// IsValidIdentifier reports whether name is a plain column or table name,
+// optionally qualified ("users.id"), safe to place in SQL unquoted.
+func IsValidIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			return false
+		}
+		for i, c := range part {
+			letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			digit := c >= '0' && c <= '9'
+			if !letter && !(digit && i > 0) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ToInt64 converts an integer column read with Get, which the driver returns
+// as int64 or, over the text protocol, as a string.
+func ToInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected type %T", value)
+	}
+}
@@ -0,0 +1,16 @@
+package utils
+
+import "testing"
+
+func TestToInt64(t *testing.T) {
+	for _, value := range []interface{}{int64(42), uint64(42), "42", []byte("42")} {
+		if n, err := ToInt64(value); err != nil || n != 42 {
+			t.Errorf("ToInt64(%#v) = %d, %v", value, n, err)
+		}
+	}
+	for _, value := range []interface{}{nil, 4.2, "x"} {
+		if _, err := ToInt64(value); err == nil {
+			t.Errorf("ToInt64(%#v): expected an error", value)
+		}
+	}
+}